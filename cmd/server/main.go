@@ -14,5 +14,6 @@ func main() {
 	flag.Parse()
 
 	container := services.NewContainer(configFile)
+	container.SchedulerService.Start()
 	routes.NewServer(container)
 }
@@ -3,6 +3,7 @@ module github.com/stjudewashere/seonaut
 go 1.23
 
 require (
+	github.com/andybalholm/brotli v1.1.1
 	github.com/antchfx/htmlquery v1.3.2
 	github.com/go-sql-driver/mysql v1.8.1
 	github.com/golang-migrate/migrate/v4 v4.17.1
@@ -8,7 +8,15 @@ import (
 
 // CrawlerConfig stores the configuration for the crawler.
 type CrawlerConfig struct {
-	Agent string `mapstructure:"agent"`
+	Agent               string `mapstructure:"agent"`
+	SecretKey           string `mapstructure:"secret_key"`           // Key used to encrypt the project secrets.
+	MaxURLs             int    `mapstructure:"max_urls"`             // Max number of URLs a crawl can have, zero for the default.
+	MaxDuration         int    `mapstructure:"max_duration"`         // Max duration of a crawl in minutes, zero for the default.
+	MaxSnapshots        int    `mapstructure:"max_snapshots"`        // Max size of the compressed snapshots of a crawl in MB, zero for the default.
+	SimilarityThreshold int    `mapstructure:"similarity_threshold"` // Min similarity percentage of near duplicate pages, zero for the default.
+	Storage             string `mapstructure:"storage"`              // Storage of the seen URLs and the crawl queue, "memory" or "disk".
+	StorageDir          string `mapstructure:"storage_dir"`          // Directory of the disk storage, empty for the temporary directory.
+	LocalDir            string `mapstructure:"local_dir"`            // Directory of the local builds the projects can crawl, empty disables them.
 }
 
 // HTTPServerConfig stores the configuration for the HTTP server.
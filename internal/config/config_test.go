@@ -18,6 +18,10 @@ func TestLoadConfig(t *testing.T) {
 	}{
 		{config.HTTPServer.Port, 9000},
 		{config.DB.Port, 3306},
+		{config.Crawler.MaxURLs, 5000},
+		{config.Crawler.MaxDuration, 60},
+		{config.Crawler.MaxSnapshots, 50},
+		{config.Crawler.SimilarityThreshold, 85},
 	}
 
 	for _, pv := range pm {
@@ -36,6 +40,9 @@ func TestLoadConfig(t *testing.T) {
 		{config.DB.Pass, "root"},
 		{config.DB.Name, "test"},
 		{config.Crawler.Agent, "testing"},
+		{config.Crawler.Storage, "disk"},
+		{config.Crawler.StorageDir, "/tmp/seonaut"},
+		{config.Crawler.LocalDir, "/srv/builds"},
 	}
 
 	for _, v := range m {
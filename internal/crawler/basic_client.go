@@ -1,10 +1,13 @@
 package crawler
 
 import (
+	"crypto/tls"
+	"io"
 	"math"
 	"net/http"
 	"net/http/httptrace"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -15,6 +18,7 @@ type HTTPRequester interface {
 type BasicClient struct {
 	Options *ClientOptions
 	client  HTTPRequester
+	tls     tlsRecorder
 }
 
 type ClientOptions struct {
@@ -22,6 +26,9 @@ type ClientOptions struct {
 	BasicAuthDomains []string
 	AuthUser         string
 	AuthPass         string
+	Headers          http.Header    // Custom headers sent to the HeaderDomains.
+	Cookies          []*http.Cookie // Cookies sent to the HeaderDomains.
+	HeaderDomains    []string       // Domains starting with a dot also match their subdomains.
 }
 
 func NewBasicClient(options *ClientOptions, client HTTPRequester) *BasicClient {
@@ -33,7 +40,18 @@ func NewBasicClient(options *ClientOptions, client HTTPRequester) *BasicClient {
 
 // Makes a request with the method specified in the method parameter to the specified URL.
 func (c *BasicClient) request(method, urlStr string) (*ClientResponse, error) {
-	req, err := http.NewRequest(method, urlStr, nil)
+	req, err := c.newRequest(method, urlStr, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.do(req)
+}
+
+// newRequest returns a new request setting the BasicAuth details as well as the custom
+// headers and cookies if the URL's domain is allowed to receive them.
+func (c *BasicClient) newRequest(method, urlStr string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, urlStr, body)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +65,17 @@ func (c *BasicClient) request(method, urlStr string) (*ClientResponse, error) {
 		req.SetBasicAuth(c.Options.AuthUser, c.Options.AuthPass)
 	}
 
-	return c.do(req)
+	if c.isHeaderDomain(domain.Host) {
+		for name, values := range c.Options.Headers {
+			req.Header[name] = values
+		}
+
+		for _, cookie := range c.Options.Cookies {
+			req.AddCookie(cookie)
+		}
+	}
+
+	return req, nil
 }
 
 // Returns true if the domain exists in the BasicAutDomains slice.
@@ -61,6 +89,22 @@ func (c *BasicClient) isBasicAuthDomain(domain string) bool {
 	return false
 }
 
+// Returns true if the domain matches any of the domains in the HeaderDomains slice.
+// Domains starting with a dot match the domain itself as well as its subdomains.
+func (c *BasicClient) isHeaderDomain(domain string) bool {
+	for _, d := range c.Options.HeaderDomains {
+		if d == domain {
+			return true
+		}
+
+		if strings.HasPrefix(d, ".") && (strings.HasSuffix(domain, d) || domain == d[1:]) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Makes a GET request to an URL and returns the http response or an error.
 func (c *BasicClient) Get(urlStr string) (*ClientResponse, error) {
 	return c.request(http.MethodGet, urlStr)
@@ -71,8 +115,23 @@ func (c *BasicClient) Head(urlStr string) (*ClientResponse, error) {
 	return c.request(http.MethodHead, urlStr)
 }
 
+// Makes a POST request to an URL with the form data and returns the http response or an error.
+func (c *BasicClient) Post(urlStr string, data url.Values) (*ClientResponse, error) {
+	req, err := c.newRequest(http.MethodPost, urlStr, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return c.do(req)
+}
+
 // do executes a request and returns its response and error.
 // It sets the client's User-Agent as well as the BasicAuth details if they are available.
+// Compressed responses are requested unless a custom Accept-Encoding header is set, and
+// the response body is decoded counting the bytes transferred. The details of the TLS
+// handshakes are recorded for each host.
 func (c *BasicClient) do(req *http.Request) (*ClientResponse, error) {
 	cr := &ClientResponse{}
 
@@ -82,9 +141,16 @@ func (c *BasicClient) do(req *http.Request) (*ClientResponse, error) {
 			// Time To First Byte in milliseconds
 			cr.TTFB = int(math.Ceil(float64(time.Since(start) / time.Millisecond)))
 		},
+		TLSHandshakeDone: func(cs tls.ConnectionState, err error) {
+			c.tls.record(newTLSInfo(req.URL, cs, err))
+		},
 	}
 
 	req.Header.Set("User-Agent", c.Options.UserAgent)
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+
 	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
 
 	resp, err := c.client.Do(req)
@@ -92,11 +158,17 @@ func (c *BasicClient) do(req *http.Request) (*ClientResponse, error) {
 		return nil, err
 	}
 
+	resp.Body = NewTransferBody(resp.Body, resp.Header.Get("Content-Encoding"))
 	cr.Response = resp
 
 	return cr, nil
 }
 
+// TLSHosts returns the TLS connection details of the hosts the client has connected to.
+func (c *BasicClient) TLSHosts() []TLSInfo {
+	return c.tls.list()
+}
+
 // GetUA returns the user-agent set for this client.
 func (c *BasicClient) GetUA() string {
 	return c.Options.UserAgent
@@ -159,3 +159,42 @@ func TestHTTPError(t *testing.T) {
 		t.Fatal("expected an error, got none")
 	}
 }
+
+// Test custom headers and cookies are only sent to the header domains.
+func TestCustomHeaders(t *testing.T) {
+	options := &crawler.ClientOptions{
+		UserAgent:     "TEST_UA",
+		Headers:       http.Header{"X-Bypass-Cdn": []string{"secret"}},
+		Cookies:       []*http.Cookie{{Name: "consent", Value: "yes"}},
+		HeaderDomains: []string{"example.com", ".example.org"},
+	}
+
+	table := map[string]bool{
+		"http://example.com":         true,
+		"http://www.example.com":     false,
+		"http://example.org":         true,
+		"http://blog.example.org":    true,
+		"http://notexample.org":      false,
+		"http://example.org.evil.io": false,
+	}
+
+	for u, sent := range table {
+		mockClient := &mockClient{}
+		client := crawler.NewBasicClient(options, mockClient)
+
+		_, err := client.Get(u)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		header := mockClient.lastRequest.Header.Get("X-Bypass-Cdn")
+		cookie, _ := mockClient.lastRequest.Cookie("consent")
+		if sent && (header != "secret" || cookie == nil || cookie.Value != "yes") {
+			t.Errorf("expected custom header and cookie to be sent to %s", u)
+		}
+
+		if !sent && (header != "" || cookie != nil) {
+			t.Errorf("expected no custom header or cookie for %s", u)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package crawler
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// BloomFilter is a probabilistic set of strings. Test may return true for strings that
+// were never added, with a false positive rate that depends on the filter's size, but
+// it always returns true for the added strings.
+type BloomFilter struct {
+	bits []uint64
+	m    uint64 // Number of bits in the filter.
+	k    uint64 // Number of hashes of each string.
+}
+
+// NewBloomFilter returns a BloomFilter sized to hold n strings with a false positive rate of p.
+func NewBloomFilter(n int, p float64) *BloomFilter {
+	n = max(n, 1)
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k := uint64(math.Max(1, math.Round(float64(m)/float64(n)*math.Ln2)))
+
+	return &BloomFilter{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+// Add adds a string to the filter.
+func (b *BloomFilter) Add(s string) {
+	h1, h2 := bloomHashes(s)
+	for i := uint64(0); i < b.k; i++ {
+		pos := (h1 + i*h2) % b.m
+		b.bits[pos/64] |= 1 << (pos % 64)
+	}
+}
+
+// Test returns true if the string may have been added to the filter.
+func (b *BloomFilter) Test(s string) bool {
+	h1, h2 := bloomHashes(s)
+	for i := uint64(0); i < b.k; i++ {
+		pos := (h1 + i*h2) % b.m
+		if b.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// bloomHashes returns two independent hashes of the string. The k hashes used by the
+// filter are derived from them using double hashing.
+func bloomHashes(s string) (uint64, uint64) {
+	a := fnv.New64a()
+	a.Write([]byte(s))
+
+	b := fnv.New64()
+	b.Write([]byte(s))
+
+	return a.Sum64(), b.Sum64() | 1
+}
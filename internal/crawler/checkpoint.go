@@ -0,0 +1,103 @@
+package crawler
+
+import (
+	"sync"
+)
+
+// Max number of changes kept in the checkpoint delta, once it is reached a checkpoint
+// is made before the checkpoint interval has elapsed.
+const maxCheckpointChanges = 10000
+
+// checkpointDelta keeps the changes in the crawler's state since the previous checkpoint,
+// so each checkpoint only stores the changes instead of the whole state. A nil delta
+// doesn't keep any changes.
+type checkpointDelta struct {
+	seen    []string
+	pending []*RequestMessage
+	queued  map[string]bool // URLs of the pending requests that haven't been processed yet.
+	done    []string
+	lock    sync.Mutex
+}
+
+func newCheckpointDelta() *checkpointDelta {
+	return &checkpointDelta{queued: make(map[string]bool)}
+}
+
+// addSeen keeps an URL added to the seen URLs.
+func (d *checkpointDelta) addSeen(u string) {
+	if d == nil {
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.seen = append(d.seen, u)
+}
+
+// addPending keeps a request added to the queue.
+func (d *checkpointDelta) addPending(r *RequestMessage) {
+	if d == nil {
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.pending = append(d.pending, r)
+	d.queued[r.URL.String()] = true
+}
+
+// addDone keeps the URL of a processed request. If the request was queued after the
+// previous checkpoint it is removed from the pending requests instead.
+func (d *checkpointDelta) addDone(u string) {
+	if d == nil {
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.queued[u] {
+		delete(d.queued, u)
+		return
+	}
+
+	d.done = append(d.done, u)
+}
+
+// size returns the number of changes kept in the delta.
+func (d *checkpointDelta) size() int {
+	if d == nil {
+		return 0
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return len(d.seen) + len(d.pending) + len(d.done)
+}
+
+// take returns a Checkpoint with the changes kept in the delta and resets it.
+func (d *checkpointDelta) take() *Checkpoint {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	cp := &Checkpoint{
+		Seen: d.seen,
+		Done: d.done,
+	}
+
+	for _, r := range d.pending {
+		if d.queued[r.URL.String()] {
+			cp.Pending = append(cp.Pending, r)
+		}
+	}
+
+	d.seen = nil
+	d.pending = nil
+	d.done = nil
+	d.queued = make(map[string]bool)
+
+	return cp
+}
@@ -0,0 +1,102 @@
+package crawler
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Value of the Accept-Encoding header sent with the requests.
+const acceptEncoding = "gzip, deflate, br"
+
+var ErrUnsupportedEncoding = errors.New("unsupported content encoding")
+
+// TransferBody is a response body that decodes the gzip, deflate and brotli content encodings
+// while it counts the bytes transferred over the network. Bodies with other encodings, such as
+// zstd if it is requested with a custom Accept-Encoding header, can't be read and return an
+// ErrUnsupportedEncoding error instead of the undecoded body.
+type TransferBody struct {
+	body     io.ReadCloser
+	counter  *countingReader
+	reader   io.Reader
+	encoding string
+}
+
+// countingReader counts the bytes read from its reader.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+
+	return n, err
+}
+
+// NewTransferBody returns a TransferBody that decodes the body according to the
+// Content-Encoding header's value.
+func NewTransferBody(body io.ReadCloser, encoding string) *TransferBody {
+	return &TransferBody{
+		body:     body,
+		counter:  &countingReader{r: body},
+		encoding: strings.ToLower(strings.TrimSpace(encoding)),
+	}
+}
+
+// Read reads the decoded body. The decoder is created on the first read, as creating
+// the gzip decoder already reads the gzip header.
+func (b *TransferBody) Read(p []byte) (int, error) {
+	if b.reader == nil {
+		r, err := b.decoder()
+		if err != nil {
+			return 0, err
+		}
+
+		b.reader = r
+	}
+
+	return b.reader.Read(p)
+}
+
+// decoder returns a reader that decodes the body's content encoding.
+func (b *TransferBody) decoder() (io.Reader, error) {
+	switch b.encoding {
+	case "gzip", "x-gzip":
+		return gzip.NewReader(b.counter)
+	case "deflate":
+		// The deflate encoding should use the zlib format, but some servers
+		// send raw deflate data so the zlib header is checked first.
+		br := bufio.NewReader(b.counter)
+		header, err := br.Peek(2)
+		if err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+			return zlib.NewReader(br)
+		}
+
+		return flate.NewReader(br), nil
+	case "br":
+		return brotli.NewReader(b.counter), nil
+	case "", "identity":
+		return b.counter, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedEncoding, b.encoding)
+}
+
+// Close closes the response body.
+func (b *TransferBody) Close() error {
+	return b.body.Close()
+}
+
+// TransferSize returns the number of bytes read from the network so far.
+func (b *TransferBody) TransferSize() int64 {
+	return b.counter.n
+}
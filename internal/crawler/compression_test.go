@@ -0,0 +1,105 @@
+package crawler_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stjudewashere/seonaut/internal/crawler"
+)
+
+// TestTransferBody tests the compressed responses are decoded and the transferred bytes counted.
+func TestTransferBody(t *testing.T) {
+	body := strings.Repeat("<p>compressible content</p>", 100)
+
+	encode := func(encoding string) []byte {
+		var b bytes.Buffer
+		var w io.WriteCloser
+		switch encoding {
+		case "gzip":
+			w = gzip.NewWriter(&b)
+		case "deflate":
+			w = zlib.NewWriter(&b)
+		case "raw-deflate":
+			w, _ = flate.NewWriter(&b, flate.DefaultCompression)
+		case "br":
+			w = brotli.NewWriter(&b)
+		default:
+			b.WriteString(body)
+			return b.Bytes()
+		}
+
+		w.Write([]byte(body))
+		w.Close()
+
+		return b.Bytes()
+	}
+
+	var acceptEncoding string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+		encoding := strings.TrimPrefix(r.URL.Path, "/")
+		if encoding == "raw-deflate" {
+			w.Header().Set("Content-Encoding", "deflate")
+		} else if encoding != "identity" {
+			w.Header().Set("Content-Encoding", encoding)
+		}
+
+		w.Write(encode(encoding))
+	}))
+	defer ts.Close()
+
+	client := crawler.NewBasicClient(&crawler.ClientOptions{UserAgent: "TEST_UA"}, &http.Client{})
+
+	for _, encoding := range []string{"gzip", "deflate", "raw-deflate", "br", "identity"} {
+		r, err := client.Get(ts.URL + "/" + encoding)
+		if err != nil {
+			t.Fatalf("%s: %v", encoding, err)
+		}
+
+		b, err := io.ReadAll(r.Response.Body)
+		if err != nil {
+			t.Fatalf("%s: %v", encoding, err)
+		}
+
+		if string(b) != body {
+			t.Errorf("%s: body was not decoded", encoding)
+		}
+
+		transferBody, ok := r.Response.Body.(*crawler.TransferBody)
+		if !ok {
+			t.Fatalf("%s: response body is not a TransferBody", encoding)
+		}
+
+		if want := int64(len(encode(encoding))); transferBody.TransferSize() != want {
+			t.Errorf("%s: expected transfer size %d got %d", encoding, want, transferBody.TransferSize())
+		}
+	}
+
+	if acceptEncoding != "gzip, deflate, br" {
+		t.Errorf("unexpected Accept-Encoding header %q", acceptEncoding)
+	}
+}
+
+// TestTransferBodyUnsupportedEncoding tests the bodies with encodings that can't be decoded
+// are not returned undecoded.
+func TestTransferBodyUnsupportedEncoding(t *testing.T) {
+	body := crawler.NewTransferBody(io.NopCloser(strings.NewReader("\x28\xb5\x2f\xfd")), "zstd")
+
+	b, err := io.ReadAll(body)
+	if !errors.Is(err, crawler.ErrUnsupportedEncoding) {
+		t.Errorf("expected ErrUnsupportedEncoding, got %v", err)
+	}
+
+	if len(b) != 0 {
+		t.Errorf("expected an empty body, got %q", b)
+	}
+}
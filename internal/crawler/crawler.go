@@ -18,20 +18,24 @@ const (
 	GET Method = iota
 	HEAD
 
-	// Random delay in milliseconds.
-	// A random delay up to this value is introduced before new HTTP requests.
-	randomDelay = 1500
+	// Default number of threads a queue will use to crawl a project.
+	defaultWorkers = 2
 
-	// Number of threads a queue will use to crawl a project.
-	consumerThreads = 2
-
-	// Crawler timeout in hours.
+	// Default crawler timeout in hours.
 	crawlerTimeout = 2
 )
 
 var ErrBlockedByRobotstxt = errors.New("blocked by robots.txt")
 var ErrVisited = errors.New("URL already visited")
 var ErrDomainNotAllowed = errors.New("domain not allowed")
+var ErrExcludedByRule = errors.New("excluded by URL rule")
+var ErrMaxDepth = errors.New("max depth exceeded")
+var ErrDirectoryLimit = errors.New("directory URL limit reached")
+
+// Reasons for a crawl to stop before crawling all the URLs.
+var ErrURLLimit = errors.New("crawl URL limit reached")
+var ErrMaxDuration = errors.New("max crawl duration reached")
+var ErrStopped = errors.New("crawler stopped")
 
 type Client interface {
 	Get(urlStr string) (*ClientResponse, error)
@@ -41,13 +45,33 @@ type Client interface {
 
 type ResponseCallback func(r *ResponseMessage)
 
+type CheckpointCallback func(c *Checkpoint)
+
 type Options struct {
-	CrawlLimit      int
-	IgnoreRobotsTxt bool
-	FollowNofollow  bool
-	IncludeNoindex  bool
-	CrawlSitemap    bool
-	AllowSubdomains bool
+	CrawlLimit           int
+	MaxDuration          time.Duration // Max duration of the crawl, it defaults to two hours.
+	IgnoreRobotsTxt      bool
+	FollowNofollow       bool
+	IncludeNoindex       bool
+	CrawlSitemap         bool
+	AllowSubdomains      bool
+	Workers              int           // Number of concurrent request consumers.
+	MinDelay             time.Duration // A random delay between MinDelay and MaxDelay
+	MaxDelay             time.Duration // is introduced before new HTTP requests.
+	MaxRequestsPerSecond float64       // Requests per second limit for each host, zero means no limit.
+	CrawlDelay           time.Duration // Overrides the robots.txt crawl delay if it is greater than zero.
+	URLRules             *URLRules     // Include and exclude rules, the start URL is always allowed.
+	Normalizer           *Normalizer   // Normalizes the URLs before they are checked and stored.
+	MaxDepth             int           // Maximum depth of the crawled URLs, zero means no limit.
+	MaxDirectoryURLs     int           // Maximum number of URLs crawled in each directory, zero means no limit.
+	ListMode             bool          // Crawls only the added URLs, without the URL rules, limits or sitemap URLs.
+	RetryAttempts        int           // Max number of attempts for failed requests, zero or one means no retries.
+	RetryBackoff         time.Duration // Delay before the first retry, it is doubled on each attempt.
+	AdaptiveThrottle     bool          // Reduces the workers and increases the delay when the server slows down.
+	Storage              *DiskStorage  // Keeps the seen URLs and queued requests on disk, nil keeps them in memory.
+	StartURLs            []*url.URL    // Additional start URLs, the URL rules and limits don't apply to them.
+	RobotsOverride       []byte        // Custom robots.txt used instead of the live file of the start URL's host.
+	MaxRedirects         int           // Max number of redirects followed to build the redirect chains.
 }
 
 type Status struct {
@@ -56,24 +80,44 @@ type Status struct {
 	Discovered int
 }
 
+// Checkpoint holds the changes in the crawler's state since the previous checkpoint, so an
+// interrupted crawl can be resumed.
+type Checkpoint struct {
+	Pending []*RequestMessage // Requests queued since the previous checkpoint.
+	Done    []string          // URLs of the requests queued before the previous checkpoint that have been processed.
+	Seen    []string          // URLs seen since the previous checkpoint.
+}
+
 type Crawler struct {
 	status           Status
 	url              *url.URL
 	options          *Options
 	queue            *Queue
-	storage          *URLStorage
-	sitemapStorage   *URLStorage
+	storage          URLStore
+	sitemapStorage   URLStore
 	sitemapChecker   *SitemapChecker
 	sitemapExists    bool
 	sitemapIsBlocked bool
 	sitemaps         []string
 	robotsChecker    *RobotsChecker
+	rateLimiter      *RateLimiter
 	allowedDomains   map[string]bool
 	mainDomain       string
 	cancel           context.CancelFunc
+	stop             context.CancelCauseFunc
+	stopReason       error
 	context          context.Context
 	client           Client
 	callback         ResponseCallback
+	checkpoint       CheckpointCallback
+	checkpointEvery  time.Duration
+	lastCheckpoint   time.Time
+	checkpointDelta  *checkpointDelta
+	directories      map[string]int
+	startURLs        map[string]bool
+	throttle         *Throttle
+	onThrottle       ThrottleCallback
+	hops             *hopCache
 }
 
 type ClientResponse struct {
@@ -83,44 +127,97 @@ type ClientResponse struct {
 
 type RequestMessage struct {
 	URL          *url.URL
+	OriginalURL  *url.URL // Set to the URL before normalization if the normalizer changed it.
 	IgnoreDomain bool
 	Method       Method
+	Depth        int // Number of links from the start URL, -1 if it is unknown.
 	Data         interface{}
 }
 
 type ResponseMessage struct {
-	URL       *url.URL
-	Response  *http.Response
-	Error     error
-	TTFB      int
-	Blocked   bool
-	InSitemap bool
-	Timeout   bool
-	Data      interface{}
+	URL         *url.URL
+	OriginalURL *url.URL
+	Response    *http.Response
+	Error       error
+	TTFB        int
+	Blocked     bool
+	InSitemap   bool
+	Timeout     bool
+	Depth       int
+	Redirects   []RedirectHop // Redirect chain of the response, nil if it is not a redirect.
+	Attempts    int           // Number of requests made, including the retries.
+	Data        interface{}
 }
 
 func NewCrawler(parsedURL *url.URL, options *Options, client Client) *Crawler {
+	parsedURL = options.Normalizer.Normalize(parsedURL)
 	mainDomain := strings.TrimPrefix(parsedURL.Host, "www.")
 
 	robotsChecker := NewRobotsChecker(client)
+	if options.RobotsOverride != nil {
+		robotsChecker.Override(parsedURL.Host, options.RobotsOverride)
+	}
 	sitemapChecker := NewSitemapChecker(client, options.CrawlLimit)
 
-	ctx, cancel := context.WithTimeout(context.Background(), crawlerTimeout*time.Hour)
+	if options.Workers <= 0 {
+		options.Workers = defaultWorkers
+	}
+
+	if options.MaxDelay < options.MinDelay {
+		options.MaxDelay = options.MinDelay
+	}
+
+	var throttle *Throttle
+	if options.AdaptiveThrottle {
+		throttle = NewThrottle(options.Workers)
+	}
+
+	maxDuration := options.MaxDuration
+	if maxDuration <= 0 {
+		maxDuration = crawlerTimeout * time.Hour
+	}
+
+	parent, stop := context.WithCancelCause(context.Background())
+	ctx, cancel := context.WithTimeoutCause(parent, maxDuration, ErrMaxDuration)
+
+	startURLs := map[string]bool{parsedURL.String(): true}
+	for _, u := range options.StartURLs {
+		startURLs[options.Normalizer.Normalize(u).String()] = true
+	}
+
+	var queue *Queue
+	var storage, sitemapStorage URLStore
+	if options.Storage != nil {
+		queue = NewBacklogQueue(options.Storage.Backlog)
+		storage = options.Storage.URLs
+		sitemapStorage = options.Storage.SitemapURLs
+	} else {
+		queue = NewQueue()
+		storage = NewURLStorage()
+		sitemapStorage = NewURLStorage()
+	}
 
 	return &Crawler{
-		status:         Status{Crawling: true},
-		url:            parsedURL,
-		options:        options,
-		queue:          NewQueue(),
-		storage:        NewURLStorage(),
-		sitemapStorage: NewURLStorage(),
-		sitemapChecker: sitemapChecker,
-		robotsChecker:  robotsChecker,
-		allowedDomains: map[string]bool{mainDomain: true, "www." + mainDomain: true},
-		mainDomain:     mainDomain,
-		cancel:         cancel,
-		context:        ctx,
-		client:         client,
+		status:          Status{Crawling: true},
+		url:             parsedURL,
+		options:         options,
+		queue:           queue,
+		storage:         storage,
+		sitemapStorage:  sitemapStorage,
+		sitemapChecker:  sitemapChecker,
+		robotsChecker:   robotsChecker,
+		rateLimiter:     NewRateLimiter(options.MaxRequestsPerSecond),
+		allowedDomains:  map[string]bool{mainDomain: true, "www." + mainDomain: true},
+		mainDomain:      mainDomain,
+		cancel:          cancel,
+		stop:            stop,
+		context:         ctx,
+		client:          client,
+		directories:     make(map[string]int),
+		startURLs:       startURLs,
+		checkpointDelta: newCheckpointDelta(),
+		throttle:        throttle,
+		hops:            newHopCache(),
 	}
 }
 
@@ -129,13 +226,63 @@ func (c *Crawler) OnResponse(r ResponseCallback) {
 	c.callback = r
 }
 
+// OnCheckpoint sets a callback that the crawler will call with its current state
+// every time the interval has elapsed.
+func (c *Crawler) OnCheckpoint(interval time.Duration, f CheckpointCallback) {
+	c.checkpoint = f
+	c.checkpointEvery = interval
+	c.lastCheckpoint = time.Now()
+}
+
+// OnThrottle sets a callback that the crawler will call with the adaptive throttle's
+// state every time its level changes.
+func (c *Crawler) OnThrottle(f ThrottleCallback) {
+	c.onThrottle = f
+}
+
+// Resume sets the number of URLs crawled before the crawl was interrupted. The crawler's
+// state is restored from the checkpoint one URL at a time with ResumeSeen and ResumePending,
+// so a resumed crawl doesn't need to load the whole checkpoint in memory. All of them must
+// be called before Start.
+func (c *Crawler) Resume(crawled int) {
+	c.status.Crawled = crawled
+}
+
+// ResumeSeen adds an URL that was seen before the crawl was interrupted. The directory counts
+// are rebuilt from the seen URLs, which may include URLs that were not crawled, so the directory
+// limits are applied conservatively after resuming.
+func (c *Crawler) ResumeSeen(u string) {
+	c.storage.Add(u)
+
+	parsed, err := url.Parse(u)
+	if err == nil && c.DomainIsAllowed(parsed.Host) {
+		c.directories[directory(parsed)]++
+	}
+}
+
+// ResumePending adds a request that was pending when the crawl was interrupted to the queue
+// without checking it again.
+func (c *Crawler) ResumePending(r *RequestMessage) {
+	c.storage.Add(r.URL.String())
+	c.queue.Push(r)
+}
+
 // Crawl starts crawling an URL and sends pagereports of the crawled URLs
-// through the pr channel. It will end when there are no more URLs to crawl
-// or the MaxPageReports limit is hit.
+// through the pr channel. It will end when there are no more URLs to crawl,
+// the crawl limit or max duration is hit or the crawler is stopped.
+// The disk storage, if any, is removed once the crawler is done.
 func (c *Crawler) Start() {
+	if c.options.Storage != nil {
+		defer c.options.Storage.Close()
+	}
 	defer c.queue.Done()
 	defer c.cancel() // cancel the consumers so all channels are closed.
 
+	// The changes are not kept if the crawler doesn't make checkpoints.
+	if c.checkpoint == nil {
+		c.checkpointDelta = nil
+	}
+
 	c.setupSitemaps()
 
 	if c.sitemapExists && c.options.CrawlSitemap {
@@ -143,7 +290,7 @@ func (c *Crawler) Start() {
 	}
 
 	sitemapLoaded := false
-	if !c.queue.Active() && c.options.CrawlSitemap {
+	if !c.queue.Active() && c.options.CrawlSitemap && !c.options.ListMode {
 		c.queueSitemapURLs()
 		sitemapLoaded = true
 	}
@@ -154,6 +301,7 @@ func (c *Crawler) Start() {
 
 	for rm := range c.crawl() {
 		c.queue.Ack(rm.URL.String())
+		c.checkpointDelta.addDone(rm.URL.String())
 
 		rm.InSitemap = c.sitemapStorage.Seen(rm.URL.String())
 		rm.Blocked = c.robotsChecker.IsBlocked(rm.URL)
@@ -165,41 +313,98 @@ func (c *Crawler) Start() {
 			c.callback(rm)
 		}
 
-		if !c.queue.Active() && c.options.CrawlSitemap && !sitemapLoaded {
+		failed := rm.Error != nil || rm.Response.StatusCode == http.StatusTooManyRequests || rm.Response.StatusCode >= 500
+		if c.throttle.Observe(rm.TTFB, failed) && c.onThrottle != nil {
+			c.onThrottle(c.throttle.State())
+		}
+
+		if c.checkpoint != nil && (time.Since(c.lastCheckpoint) >= c.checkpointEvery || c.checkpointDelta.size() >= maxCheckpointChanges) {
+			c.checkpoint(c.checkpointDelta.take())
+			c.lastCheckpoint = time.Now()
+		}
+
+		if !c.queue.Active() && c.options.CrawlSitemap && !c.options.ListMode && !sitemapLoaded {
 			c.queueSitemapURLs()
 			sitemapLoaded = true
 		}
 
-		if !c.queue.Active() || c.status.Crawled >= c.options.CrawlLimit {
+		if !c.queue.Active() {
 			break
 		}
+
+		if c.status.Crawled >= c.options.CrawlLimit {
+			c.stopReason = ErrURLLimit
+			break
+		}
+	}
+
+	if c.stopReason == nil && c.context.Err() != nil {
+		c.stopReason = context.Cause(c.context)
 	}
 }
 
 // AddRequest processes a request message for the crawler.
-// It checks if the URL has already been visited, validates the domain and checks
-// if it is blocked in the the robots.txt rules. It returns an error if any of the checks
-// fails. Finally, it adds the request to the processing queue.
+// The request's URL is normalized first, keeping the original URL in the request if it changed.
+// It checks if the URL has already been visited, validates the domain and that the client can
+// request it, checks the URL rules, the depth and directory limits and if it is blocked in the
+// the robots.txt rules.
+// It returns an error if any of the checks fails. Finally, it adds the request to the
+// processing queue. The URL rules and limits don't apply to the start URLs, the requests
+// that ignore the domain, such as resources, and the requests added in list mode.
 func (c *Crawler) AddRequest(r *RequestMessage) error {
+	if n := c.Normalize(r.URL); n.String() != r.URL.String() {
+		r.OriginalURL = r.URL
+		r.URL = n
+	}
+
 	if c.storage.Seen(r.URL.String()) {
 		return ErrVisited
 	}
 
 	c.storage.Add(r.URL.String())
+	c.checkpointDelta.addSeen(r.URL.String())
 
-	if !c.domainIsAllowed(r.URL.Host) && !r.IgnoreDomain {
+	if !c.DomainIsAllowed(r.URL.Host) && !r.IgnoreDomain {
 		return ErrDomainNotAllowed
 	}
 
+	if !c.clientServes(r.URL) {
+		return ErrOutsideBaseURL
+	}
+
+	limited := !r.IgnoreDomain && !c.options.ListMode && !c.startURLs[r.URL.String()]
+
+	if limited && !c.options.URLRules.Allowed(r.URL) {
+		return ErrExcludedByRule
+	}
+
+	if limited && c.options.MaxDepth > 0 && r.Depth > c.options.MaxDepth {
+		return ErrMaxDepth
+	}
+
+	if limited && c.directoryLimitReached(r.URL) {
+		return ErrDirectoryLimit
+	}
+
 	if !c.options.IgnoreRobotsTxt && c.robotsChecker.IsBlocked(r.URL) {
 		return ErrBlockedByRobotstxt
 	}
 
+	if !r.IgnoreDomain {
+		c.directories[directory(r.URL)]++
+	}
+
 	c.queue.Push(r)
+	c.checkpointDelta.addPending(r)
 
 	return nil
 }
 
+// Normalize returns the URL normalized with the crawler's Normalizer option.
+func (c *Crawler) Normalize(u *url.URL) *url.URL {
+	return c.options.Normalizer.Normalize(u)
+}
+
 // GetStatus returns the current cralwer status.
 func (c *Crawler) GetStatus() Status {
 	c.status.Discovered = c.queue.Count()
@@ -208,16 +413,50 @@ func (c *Crawler) GetStatus() Status {
 	return c.status
 }
 
+// StartURLs returns the crawler's additional start URLs.
+func (c *Crawler) StartURLs() []*url.URL {
+	return c.options.StartURLs
+}
+
 // Returns true if the sitemap.xml file exists.
 func (c *Crawler) SitemapExists() bool {
 	return c.sitemapExists
 }
 
+// TLSHosts returns the TLS connection details of the crawled hosts if the client records them.
+func (c *Crawler) TLSHosts() []TLSInfo {
+	if r, ok := c.client.(interface{ TLSHosts() []TLSInfo }); ok {
+		return r.TLSHosts()
+	}
+
+	return []TLSInfo{}
+}
+
+// clientServes returns false if the client can't request the URL, as it happens with the
+// URLs outside the base URL of the DirClient.
+func (c *Crawler) clientServes(u *url.URL) bool {
+	if s, ok := c.client.(interface{ Serves(*url.URL) bool }); ok {
+		return s.Serves(u)
+	}
+
+	return true
+}
+
+// RobotsFiles returns the robots.txt files of the hosts requested during the crawl.
+func (c *Crawler) RobotsFiles() []RobotsFile {
+	return c.robotsChecker.Files()
+}
+
 // Returns true if the robots.txt file exists.
 func (c *Crawler) RobotstxtExists() bool {
 	return c.robotsChecker.Exists(c.url)
 }
 
+// Returns the effective delay between requests for the website's host.
+func (c *Crawler) CrawlDelay() time.Duration {
+	return c.crawlDelay(c.url)
+}
+
 // Returns true if any of the website's sitemaps is blocked in the robots.txt file.
 func (c *Crawler) SitemapIsBlocked() bool {
 	return c.sitemapIsBlocked
@@ -225,7 +464,24 @@ func (c *Crawler) SitemapIsBlocked() bool {
 
 // Stops the cralwer by canceling the cralwer context.
 func (c *Crawler) Stop() {
+	c.stop(ErrStopped)
+}
+
+// Discard releases the resources of a crawler that is not going to be started, such as
+// its queue and disk storage.
+func (c *Crawler) Discard() {
 	c.cancel()
+	c.queue.Done()
+
+	if c.options.Storage != nil {
+		c.options.Storage.Close()
+	}
+}
+
+// StopReason returns the reason the crawl stopped before crawling all the URLs, which is
+// one of ErrURLLimit, ErrMaxDuration or ErrStopped. It returns nil if the crawl was complete.
+func (c *Crawler) StopReason() error {
+	return c.stopReason
 }
 
 // setupSitemaps checks if any sitemap exists for the crawler's url. It checks the robots file
@@ -266,14 +522,14 @@ func (c *Crawler) crawl() <-chan *ResponseMessage {
 	respStream := make(chan *ResponseMessage)
 
 	wg := new(sync.WaitGroup)
-	wg.Add(consumerThreads)
+	wg.Add(c.options.Workers)
 
 	// Starts the consumers that will make the client requests
-	for i := 0; i < consumerThreads; i++ {
-		go func() {
+	for i := 0; i < c.options.Workers; i++ {
+		go func(id int) {
 			defer wg.Done()
-			c.consumer(reqStream, respStream)
-		}()
+			c.consumer(id, reqStream, respStream)
+		}(i)
 	}
 
 	// Polls URLs from the queue and send them to the requests stream so they can
@@ -283,11 +539,18 @@ func (c *Crawler) crawl() <-chan *ResponseMessage {
 		defer close(respStream)
 		defer wg.Wait()
 
+		// The queue is polled with the context as it may be empty until the requests
+		// being consumed are done, which doesn't happen if the crawler is stopped.
 		for {
+			r, ok := c.queue.PollContext(c.context)
+			if !ok {
+				return
+			}
+
 			select {
 			case <-c.context.Done():
 				return
-			case reqStream <- c.queue.Poll():
+			case reqStream <- r:
 			}
 		}
 	}()
@@ -296,30 +559,51 @@ func (c *Crawler) crawl() <-chan *ResponseMessage {
 }
 
 // Consumer gets URLs from the reqStream until the context is cancelled.
-// It adds a random delay between client calls.
-func (c *Crawler) consumer(reqStream <-chan *RequestMessage, respStream chan<- *ResponseMessage) {
+// It adds a random delay between client calls and waits for the host's rate limiter.
+// The redirect chains of the responses are followed before they are sent to the respStream.
+// The consumer's id is used to pause it when the adaptive throttle reduces the workers.
+func (c *Crawler) consumer(id int, reqStream <-chan *RequestMessage, respStream chan<- *ResponseMessage) {
 	for {
+		// Pause the consumer while the adaptive throttle doesn't allow this many workers.
+		for id >= c.throttle.Workers() {
+			if err := c.sleep(throttleWaitInterval); err != nil {
+				return
+			}
+		}
+
 		select {
 		case requestMessage := <-reqStream:
 			// Add random delay to avoid overwhelming the servers with requests.
-			time.Sleep(time.Duration(rand.Intn(randomDelay)) * time.Millisecond)
+			if err := c.sleep(c.randomDelay() + c.throttle.Delay()); err != nil {
+				return
+			}
 
-			rm := &ResponseMessage{
-				URL:  requestMessage.URL,
-				Data: requestMessage.Data,
+			c.rateLimiter.SetDelay(requestMessage.URL.Host, c.crawlDelay(requestMessage.URL))
+			if err := c.rateLimiter.Wait(c.context, requestMessage.URL.Host); err != nil {
+				return
 			}
 
-			r := &ClientResponse{}
-			switch requestMessage.Method {
-			case GET:
-				r, rm.Error = c.client.Get(requestMessage.URL.String())
-			case HEAD:
-				r, rm.Error = c.client.Head(requestMessage.URL.String())
+			rm := &ResponseMessage{
+				URL:         requestMessage.URL,
+				OriginalURL: requestMessage.OriginalURL,
+				Depth:       requestMessage.Depth,
+				Data:        requestMessage.Data,
 			}
 
+			var r *ClientResponse
+			r, rm.Attempts, rm.Error = c.request(requestMessage)
+
 			if rm.Error == nil {
 				rm.Response = r.Response
 				rm.TTFB = r.TTFB
+
+				c.hops.add(newRedirectHop(rm.URL.String(), r.Response))
+				rm.Redirects = c.followRedirects(rm.URL, r.Response)
+			}
+
+			// The crawler was stopped while waiting to retry the request or to follow its redirects.
+			if c.context.Err() != nil {
+				return
 			}
 
 			respStream <- rm
@@ -329,6 +613,31 @@ func (c *Crawler) consumer(reqStream <-chan *RequestMessage, respStream chan<- *
 	}
 }
 
+// crawlDelay returns the delay between requests for the URL's host. The CrawlDelay option
+// takes precedence over the delay in the robots.txt file, which is not used if the crawler
+// is ignoring the robots.txt file.
+func (c *Crawler) crawlDelay(u *url.URL) time.Duration {
+	if c.options.CrawlDelay > 0 {
+		return c.options.CrawlDelay
+	}
+
+	if c.options.IgnoreRobotsTxt {
+		return 0
+	}
+
+	return c.robotsChecker.CrawlDelay(u)
+}
+
+// randomDelay returns a random duration between the MinDelay and MaxDelay options.
+func (c *Crawler) randomDelay() time.Duration {
+	delay := c.options.MinDelay
+	if c.options.MaxDelay > c.options.MinDelay {
+		delay += time.Duration(rand.Int63n(int64(c.options.MaxDelay - c.options.MinDelay)))
+	}
+
+	return delay
+}
+
 // Callback to load sitemap URLs into the sitemap storage.
 func (c *Crawler) loadSitemapURLs(u string) {
 	l, err := url.Parse(u)
@@ -340,28 +649,55 @@ func (c *Crawler) loadSitemapURLs(u string) {
 		l.Path = "/"
 	}
 
-	c.sitemapStorage.Add(l.String())
+	c.sitemapStorage.Add(c.Normalize(l).String())
 }
 
-// queueSitemapURLs loops through the sitemap's URLs, adding any unseen URLs to the crawler's queue.
+// queueSitemapURLs loops through the sitemap's URLs, adding any unseen URLs that are allowed
+// by the URL rules and directory limits to the crawler's queue. The depth of the sitemap URLs
+// is unknown.
 func (c *Crawler) queueSitemapURLs() {
 	c.sitemapStorage.Iterate(func(v string) {
 		if !c.storage.Seen(v) {
 			c.storage.Add(v)
+			c.checkpointDelta.addSeen(v)
 			u, err := url.Parse(v)
 			if err != nil {
 				return
 			}
 
-			c.queue.Push(&RequestMessage{URL: u})
+			if !c.options.URLRules.Allowed(u) || c.directoryLimitReached(u) {
+				return
+			}
+
+			c.directories[directory(u)]++
+			r := &RequestMessage{URL: u, Depth: -1}
+			c.queue.Push(r)
+			c.checkpointDelta.addPending(r)
 		}
 	})
 }
 
-// Returns true if the crawler is allowed to crawl the domain, checking the allowedDomains slice.
+// directoryLimitReached returns true if the MaxDirectoryURLs option is set and the URL's
+// directory already has the maximum number of URLs.
+func (c *Crawler) directoryLimitReached(u *url.URL) bool {
+	return c.options.MaxDirectoryURLs > 0 && c.directories[directory(u)] >= c.options.MaxDirectoryURLs
+}
+
+// directory returns the URL's host and path up to the last slash, so the URLs
+// "/blog/post" and "/blog/?page=2" are in the same "/blog/" directory.
+func directory(u *url.URL) string {
+	i := strings.LastIndex(u.Path, "/")
+	if i < 0 {
+		return u.Host + "/"
+	}
+
+	return u.Host + u.Path[:i+1]
+}
+
+// DomainIsAllowed returns true if the crawler is allowed to crawl the domain, checking the allowedDomains slice.
 // If the AllowSubdomains option is set, returns true the given domain is a subdomain of the
 // crawlers's base domain.
-func (c *Crawler) domainIsAllowed(d string) bool {
+func (c *Crawler) DomainIsAllowed(d string) bool {
 	_, ok := c.allowedDomains[d]
 	if ok {
 		return true
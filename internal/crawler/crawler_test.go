@@ -0,0 +1,278 @@
+package crawler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+)
+
+func mustParse(t *testing.T, s string) *url.URL {
+	u, err := url.Parse(s)
+	if err != nil {
+		t.Fatalf("url parse error %v", err)
+	}
+
+	return u
+}
+
+// TestAddRequestMaxDepth tests the requests deeper than the MaxDepth option are not added.
+func TestAddRequestMaxDepth(t *testing.T) {
+	start := mustParse(t, "https://example.com/")
+	c := crawler.NewCrawler(start, &crawler.Options{MaxDepth: 2}, &MockClient{})
+
+	table := []struct {
+		url   string
+		depth int
+		err   error
+	}{
+		{"https://example.com/", 0, nil},
+		{"https://example.com/a", 2, nil},
+		{"https://example.com/b", 3, crawler.ErrMaxDepth},
+		{"https://example.com/c", -1, nil},
+	}
+
+	for _, tt := range table {
+		err := c.AddRequest(&crawler.RequestMessage{URL: mustParse(t, tt.url), Depth: tt.depth})
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s at depth %d: expected error %v got %v", tt.url, tt.depth, tt.err, err)
+		}
+	}
+}
+
+// TestAddRequestDirectoryLimit tests the requests are limited per directory and that
+// the requests ignoring the domain, such as resources, are not limited.
+func TestAddRequestDirectoryLimit(t *testing.T) {
+	start := mustParse(t, "https://example.com/")
+	c := crawler.NewCrawler(start, &crawler.Options{MaxDirectoryURLs: 2}, &MockClient{})
+
+	table := []struct {
+		url          string
+		ignoreDomain bool
+		err          error
+	}{
+		{"https://example.com/shop/?color=red", false, nil},
+		{"https://example.com/shop/?color=blue", false, nil},
+		{"https://example.com/shop/?color=green", false, crawler.ErrDirectoryLimit},
+		{"https://example.com/shop/shoes/", false, nil},
+		{"https://example.com/shop/style.css", true, nil},
+		{"https://example.com/about", false, nil},
+	}
+
+	for _, tt := range table {
+		err := c.AddRequest(&crawler.RequestMessage{URL: mustParse(t, tt.url), IgnoreDomain: tt.ignoreDomain})
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected error %v got %v", tt.url, tt.err, err)
+		}
+	}
+}
+
+// TestAddRequestListMode tests the URL rules and limits don't apply in list mode.
+func TestAddRequestListMode(t *testing.T) {
+	rules, err := crawler.ParseURLRules("exclude regex:/private")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	start := mustParse(t, "https://example.com/")
+	c := crawler.NewCrawler(start, &crawler.Options{URLRules: rules, MaxDepth: 1, ListMode: true}, &MockClient{})
+
+	table := []struct {
+		url string
+		err error
+	}{
+		{"https://example.com/private", nil},
+		{"https://example.com/a/b/c", nil},
+		{"https://example.com/private", crawler.ErrVisited},
+		{"https://example.org/", crawler.ErrDomainNotAllowed},
+	}
+
+	for _, tt := range table {
+		err := c.AddRequest(&crawler.RequestMessage{URL: mustParse(t, tt.url), Depth: 5})
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected error %v got %v", tt.url, tt.err, err)
+		}
+	}
+}
+
+// TestAddRequestStartURLs tests the URL rules don't apply to the additional start URLs.
+func TestAddRequestStartURLs(t *testing.T) {
+	rules, err := crawler.ParseURLRules("include /blog/*")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	start := mustParse(t, "https://example.com/")
+	options := &crawler.Options{URLRules: rules, StartURLs: []*url.URL{mustParse(t, "https://example.com/store/")}}
+	c := crawler.NewCrawler(start, options, &MockClient{})
+
+	table := []struct {
+		url string
+		err error
+	}{
+		{"https://example.com/", nil},
+		{"https://example.com/store/", nil},
+		{"https://example.com/store/item", crawler.ErrExcludedByRule},
+		{"https://example.com/blog/post", nil},
+	}
+
+	for _, tt := range table {
+		err := c.AddRequest(&crawler.RequestMessage{URL: mustParse(t, tt.url)})
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected error %v got %v", tt.url, tt.err, err)
+		}
+	}
+}
+
+// TestStopReason tests the crawler reports the reason a crawl stopped before crawling all the URLs.
+func TestStopReason(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	table := []struct {
+		options *crawler.Options
+		stop    bool
+		want    error
+	}{
+		{&crawler.Options{CrawlLimit: 10}, false, nil},
+		{&crawler.Options{CrawlLimit: 2}, false, crawler.ErrURLLimit},
+		{&crawler.Options{CrawlLimit: 10, MaxDuration: time.Nanosecond}, false, crawler.ErrMaxDuration},
+		{&crawler.Options{CrawlLimit: 10}, true, crawler.ErrStopped},
+	}
+
+	for _, tc := range table {
+		client := crawler.NewBasicClient(&crawler.ClientOptions{UserAgent: "TEST_UA"}, newJarClient())
+		c := crawler.NewCrawler(mustParse(t, ts.URL+"/"), tc.options, client)
+		for _, p := range []string{"/", "/a", "/b", "/c"} {
+			c.AddRequest(&crawler.RequestMessage{URL: mustParse(t, ts.URL+p)})
+		}
+
+		if tc.stop {
+			c.Stop()
+		}
+
+		c.Start()
+
+		if err := c.StopReason(); !errors.Is(err, tc.want) {
+			t.Errorf("%+v: expected stop reason %v got %v", tc.options, tc.want, err)
+		}
+	}
+}
+
+// TestStopDuringDelay tests a crawl can be stopped while its consumers wait for the delay
+// between requests.
+func TestStopDuringDelay(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	client := crawler.NewBasicClient(&crawler.ClientOptions{UserAgent: "TEST_UA"}, newJarClient())
+	c := crawler.NewCrawler(mustParse(t, ts.URL+"/"), &crawler.Options{CrawlLimit: 10, MinDelay: time.Hour}, client)
+	c.AddRequest(&crawler.RequestMessage{URL: mustParse(t, ts.URL+"/")})
+
+	time.AfterFunc(100*time.Millisecond, c.Stop)
+
+	start := time.Now()
+	c.Start()
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the crawl to stop during the delay, it took %v", elapsed)
+	}
+}
+
+// TestCheckpointChanges tests each checkpoint only has the changes since the previous one,
+// and that applying all of them leaves the seen URLs and no pending requests.
+func TestCheckpointChanges(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	client := crawler.NewBasicClient(&crawler.ClientOptions{UserAgent: "TEST_UA"}, newJarClient())
+	c := crawler.NewCrawler(mustParse(t, ts.URL+"/"), &crawler.Options{CrawlLimit: 10}, client)
+	for _, p := range []string{"/", "/a", "/b", "/c"} {
+		c.AddRequest(&crawler.RequestMessage{URL: mustParse(t, ts.URL+p)})
+	}
+
+	c.OnResponse(func(r *crawler.ResponseMessage) {
+		if r.URL.Path == "/a" {
+			c.AddRequest(&crawler.RequestMessage{URL: mustParse(t, ts.URL+"/d")})
+		}
+	})
+
+	pending := make(map[string]bool)
+	seen := make(map[string]bool)
+	checkpoints := 0
+	c.OnCheckpoint(0, func(cp *crawler.Checkpoint) {
+		checkpoints++
+		for _, u := range cp.Done {
+			if !pending[u] {
+				t.Errorf("done URL %s was not pending in a previous checkpoint", u)
+			}
+			delete(pending, u)
+		}
+
+		for _, r := range cp.Pending {
+			pending[r.URL.String()] = true
+		}
+
+		for _, u := range cp.Seen {
+			if seen[u] {
+				t.Errorf("seen URL %s was already in a previous checkpoint", u)
+			}
+			seen[u] = true
+		}
+	})
+
+	c.Start()
+
+	if checkpoints != 5 {
+		t.Errorf("expected 5 checkpoints, got %d", checkpoints)
+	}
+
+	if len(pending) != 0 {
+		t.Errorf("expected no pending requests, got %v", pending)
+	}
+
+	if len(seen) != 5 {
+		t.Errorf("expected 5 seen URLs, got %v", seen)
+	}
+}
+
+// TestResume tests a crawl with disk storage resumes from the seen URLs and pending requests.
+func TestResume(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	storage, err := crawler.NewDiskStorage(t.TempDir(), 10)
+	if err != nil {
+		t.Fatalf("NewDiskStorage: %v", err)
+	}
+
+	client := crawler.NewBasicClient(&crawler.ClientOptions{UserAgent: "TEST_UA"}, newJarClient())
+	c := crawler.NewCrawler(mustParse(t, ts.URL+"/"), &crawler.Options{CrawlLimit: 10, Storage: storage}, client)
+
+	c.Resume(1)
+	c.ResumeSeen(ts.URL + "/")
+	c.ResumeSeen(ts.URL + "/a")
+	c.ResumePending(&crawler.RequestMessage{URL: mustParse(t, ts.URL+"/a")})
+
+	crawled := []string{}
+	c.OnResponse(func(r *crawler.ResponseMessage) {
+		crawled = append(crawled, r.URL.Path)
+		if err := c.AddRequest(&crawler.RequestMessage{URL: mustParse(t, ts.URL+"/")}); err != crawler.ErrVisited {
+			t.Errorf("expected seen URL to be visited, got %v", err)
+		}
+	})
+
+	c.Start()
+
+	if len(crawled) != 1 || crawled[0] != "/a" {
+		t.Errorf("expected only the pending request to be crawled, got %v", crawled)
+	}
+
+	if s := c.GetStatus(); s.Crawled != 2 {
+		t.Errorf("expected 2 crawled URLs, got %d", s.Crawled)
+	}
+}
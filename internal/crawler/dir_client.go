@@ -0,0 +1,191 @@
+package crawler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Name of the file served for the directory URLs.
+const dirIndexFile = "index.html"
+
+// Name of the file served as the body of the 404 responses if it exists.
+const dirNotFoundFile = "404.html"
+
+var ErrOutsideBaseURL = errors.New("the URL is not served from the local directory")
+
+// DirClient is a Client that serves the requests from a local directory, such as the output
+// of a static site generator, instead of the network. The URLs under the base URL are mapped
+// to the directory's files, the directory URLs are served with their index.html file and the
+// missing files get a 404 response with the 404.html file if it exists.
+type DirClient struct {
+	dir       string
+	baseURL   *url.URL
+	userAgent string
+}
+
+func NewDirClient(dir string, baseURL *url.URL, userAgent string) *DirClient {
+	return &DirClient{
+		dir:       dir,
+		baseURL:   baseURL,
+		userAgent: userAgent,
+	}
+}
+
+// Returns the response of the file the URL maps to.
+func (c *DirClient) Get(urlStr string) (*ClientResponse, error) {
+	return c.request(http.MethodGet, urlStr)
+}
+
+// Returns the response of the file the URL maps to without its body.
+func (c *DirClient) Head(urlStr string) (*ClientResponse, error) {
+	return c.request(http.MethodHead, urlStr)
+}
+
+// GetUA returns the user-agent set for this client.
+func (c *DirClient) GetUA() string {
+	return c.userAgent
+}
+
+// Serves returns true if the URL is under the base URL, so it can be served from the directory.
+func (c *DirClient) Serves(u *url.URL) bool {
+	_, ok := c.filePath(u)
+	return ok
+}
+
+// request returns the response of the file the URL maps to. The directory URLs without a
+// trailing slash are redirected to the URL with the slash. It returns an error if the URL
+// is not under the base URL.
+func (c *DirClient) request(method, urlStr string) (*ClientResponse, error) {
+	resp, err := c.serve(method, urlStr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClientResponse{Response: resp}, nil
+}
+
+// serve returns a response with the file the URL maps to.
+func (c *DirClient) serve(method, urlStr string) (*http.Response, error) {
+	req, err := http.NewRequest(method, urlStr, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
+
+	name, ok := c.filePath(req.URL)
+	if !ok {
+		return nil, ErrOutsideBaseURL
+	}
+
+	info, err := os.Stat(name)
+	if err == nil && info.IsDir() {
+		if !strings.HasSuffix(req.URL.Path, "/") {
+			location := *req.URL
+			location.Path += "/"
+			resp := newDirResponse(req, http.StatusMovedPermanently, nil, "")
+			resp.Header.Set("Location", location.String())
+			return resp, nil
+		}
+
+		name = filepath.Join(name, dirIndexFile)
+	}
+
+	f, err := os.Open(name)
+	if errors.Is(err, os.ErrNotExist) {
+		body, _ := os.ReadFile(filepath.Join(c.dir, dirNotFoundFile))
+		return newDirResponse(req, http.StatusNotFound, body, dirNotFoundFile), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err = f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := newDirResponse(req, http.StatusOK, body, name)
+	resp.Header.Set("Last-Modified", info.ModTime().UTC().Format(http.TimeFormat))
+
+	return resp, nil
+}
+
+// filePath returns the path of the file the URL maps to. It returns false if the URL
+// is not under the base URL. The URL's path is cleaned so the files outside the
+// directory can't be served.
+func (c *DirClient) filePath(u *url.URL) (string, bool) {
+	if u.Scheme != c.baseURL.Scheme || u.Host != c.baseURL.Host {
+		return "", false
+	}
+
+	base := "/" + strings.TrimPrefix(c.baseURL.Path, "/")
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+
+	p := u.Path
+	if p+"/" == base {
+		p = base
+	}
+
+	rel, ok := strings.CutPrefix(p, base)
+	if !ok {
+		return "", false
+	}
+
+	return filepath.Join(c.dir, filepath.FromSlash(path.Clean("/"+rel))), true
+}
+
+// newDirResponse returns a response with the body and the content type guessed from the
+// file's extension or its content. The body is left empty in the responses to HEAD requests.
+func newDirResponse(req *http.Request, statusCode int, body []byte, name string) *http.Response {
+	header := make(http.Header)
+	header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+
+	if len(body) > 0 {
+		contentType := mime.TypeByExtension(filepath.Ext(name))
+		if contentType == "" {
+			contentType = http.DetectContentType(body)
+		}
+
+		header.Set("Content-Type", contentType)
+	}
+
+	resp := &http.Response{
+		Status:        strconv.Itoa(statusCode) + " " + http.StatusText(statusCode),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+
+	if req.Method == http.MethodHead {
+		body = nil
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return resp
+}
@@ -0,0 +1,158 @@
+package crawler_test
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+)
+
+// newTestDir creates a directory with the files of a static site.
+func newTestDir(t *testing.T) string {
+	dir := t.TempDir()
+	files := map[string]string{
+		"index.html":      "<html><body>Home</body></html>",
+		"404.html":        "<html><body>Not found</body></html>",
+		"blog/index.html": "<html><body>Blog</body></html>",
+		"style.css":       "body { color: red; }",
+		"data":            "%PDF-1.4",
+	}
+
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestDirClient(t *testing.T) {
+	base, _ := url.Parse("https://example.com/")
+	client := crawler.NewDirClient(newTestDir(t), base, "TEST UA")
+
+	table := []struct {
+		url         string
+		statusCode  int
+		contentType string
+		body        string
+	}{
+		{"https://example.com/", http.StatusOK, "text/html; charset=utf-8", "Home"},
+		{"https://example.com/blog/", http.StatusOK, "text/html; charset=utf-8", "Blog"},
+		{"https://example.com/style.css", http.StatusOK, "text/css; charset=utf-8", "color"},
+		{"https://example.com/data", http.StatusOK, "application/pdf", "%PDF"},
+		{"https://example.com/missing", http.StatusNotFound, "text/html; charset=utf-8", "Not found"},
+		{"https://example.com/../index.html", http.StatusOK, "text/html; charset=utf-8", "Home"},
+		{"https://example.com/blog", http.StatusMovedPermanently, "", ""},
+	}
+
+	for _, v := range table {
+		r, err := client.Get(v.url)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", v.url, err)
+		}
+
+		body, _ := io.ReadAll(r.Response.Body)
+		r.Response.Body.Close()
+
+		if r.Response.StatusCode != v.statusCode {
+			t.Errorf("%s: expected status code %d, got %d", v.url, v.statusCode, r.Response.StatusCode)
+		}
+
+		if ct := r.Response.Header.Get("Content-Type"); ct != v.contentType {
+			t.Errorf("%s: expected content type %q, got %q", v.url, v.contentType, ct)
+		}
+
+		if !strings.Contains(string(body), v.body) {
+			t.Errorf("%s: expected body containing %q, got %q", v.url, v.body, body)
+		}
+
+		if r.Response.Request.URL.String() != v.url {
+			t.Errorf("%s: expected request URL, got %s", v.url, r.Response.Request.URL)
+		}
+	}
+}
+
+func TestDirClientRedirect(t *testing.T) {
+	base, _ := url.Parse("https://example.com/")
+	client := crawler.NewDirClient(newTestDir(t), base, "TEST UA")
+
+	r, err := client.Get("https://example.com/blog")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if l := r.Response.Header.Get("Location"); l != "https://example.com/blog/" {
+		t.Errorf("expected redirect to https://example.com/blog/, got %q", l)
+	}
+}
+
+func TestDirClientHead(t *testing.T) {
+	base, _ := url.Parse("https://example.com/")
+	client := crawler.NewDirClient(newTestDir(t), base, "TEST UA")
+
+	r, err := client.Head("https://example.com/")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	body, _ := io.ReadAll(r.Response.Body)
+	if len(body) != 0 || r.Response.ContentLength == 0 {
+		t.Errorf("expected empty body with the file's content length, got %d bytes and %d", len(body), r.Response.ContentLength)
+	}
+}
+
+// TestDirClientBaseURL tests only the URLs under the base URL are served.
+func TestDirClientBaseURL(t *testing.T) {
+	base, _ := url.Parse("https://example.com/docs")
+	client := crawler.NewDirClient(newTestDir(t), base, "TEST UA")
+
+	r, err := client.Get("https://example.com/docs/blog/")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if r.Response.StatusCode != http.StatusOK {
+		t.Errorf("expected status code 200, got %d", r.Response.StatusCode)
+	}
+
+	for _, u := range []string{"https://example.com/blog/", "http://example.com/docs/", "https://other.example.com/docs/"} {
+		if _, err := client.Get(u); err != crawler.ErrOutsideBaseURL {
+			t.Errorf("%s: expected ErrOutsideBaseURL, got %v", u, err)
+		}
+	}
+}
+
+// TestDirClientAddRequest tests the crawler doesn't queue the URLs the DirClient can't serve,
+// such as the resources in other hosts or the URLs of the www subdomain.
+func TestDirClientAddRequest(t *testing.T) {
+	base, _ := url.Parse("https://example.com/")
+	client := crawler.NewDirClient(newTestDir(t), base, "TEST UA")
+	c := crawler.NewCrawler(base, &crawler.Options{CrawlLimit: 10, IgnoreRobotsTxt: true}, client)
+	defer c.Discard()
+
+	table := []struct {
+		r    *crawler.RequestMessage
+		want error
+	}{
+		{&crawler.RequestMessage{URL: mustParse(t, "https://example.com/blog/")}, nil},
+		{&crawler.RequestMessage{URL: mustParse(t, "https://www.example.com/")}, crawler.ErrOutsideBaseURL},
+		{&crawler.RequestMessage{URL: mustParse(t, "https://cdn.example.net/app.js"), IgnoreDomain: true}, crawler.ErrOutsideBaseURL},
+	}
+
+	for _, tt := range table {
+		if err := c.AddRequest(tt.r); err != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.r.URL, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package crawler
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// False positive rate of the Bloom filters of the disk URL stores. A false positive
+	// means an URL that was never seen is skipped by the crawler.
+	bloomFalsePositiveRate = 0.0001
+
+	// Number of URLs the seen URLs filter is sized for on each crawled page, as the
+	// seen URLs include the external links and resources of the pages.
+	seenURLsPerPage = 10
+
+	// Max length in bytes of the lines read from the storage files.
+	maxStorageLine = 1024 * 1024
+)
+
+// DiskStorage holds the crawler's stores for the seen URLs, the sitemap URLs and the
+// queued requests in a temporary directory, so the crawler's memory use doesn't grow
+// with the number of URLs. The crawler removes the directory when the crawl ends.
+type DiskStorage struct {
+	dir         string
+	URLs        *DiskURLStorage
+	SitemapURLs *DiskURLStorage
+	Backlog     *DiskBacklog
+}
+
+// NewDiskStorage creates the disk stores in a new temporary directory inside dir, or in the
+// default directory for temporary files if dir is empty. The Bloom filters of the URL stores
+// are sized for crawls of up to crawlLimit URLs.
+func NewDiskStorage(dir string, crawlLimit int) (*DiskStorage, error) {
+	tmp, err := os.MkdirTemp(dir, "seonaut-crawl-")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &DiskStorage{dir: tmp}
+
+	s.URLs, err = NewDiskURLStorage(filepath.Join(tmp, "seen"), crawlLimit*seenURLsPerPage)
+	if err == nil {
+		s.SitemapURLs, err = NewDiskURLStorage(filepath.Join(tmp, "sitemap"), crawlLimit)
+	}
+
+	if err == nil {
+		s.Backlog, err = NewDiskBacklog(filepath.Join(tmp, "queue"))
+	}
+
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Close closes the storage files and removes the temporary directory.
+func (s *DiskStorage) Close() error {
+	var errs []error
+	if s.URLs != nil {
+		errs = append(errs, s.URLs.Close())
+	}
+
+	if s.SitemapURLs != nil {
+		errs = append(errs, s.SitemapURLs.Close())
+	}
+
+	if s.Backlog != nil {
+		errs = append(errs, s.Backlog.Close())
+	}
+
+	errs = append(errs, os.RemoveAll(s.dir))
+
+	return errors.Join(errs...)
+}
+
+// DiskURLStorage is an URLStore that uses a Bloom filter to check if the URLs have been seen,
+// and appends the URLs to a file so they can be iterated.
+type DiskURLStorage struct {
+	path   string
+	file   *os.File
+	writer *bufio.Writer
+	filter *BloomFilter
+	lock   sync.RWMutex
+}
+
+// NewDiskURLStorage creates the file of the URL store. Its Bloom filter is sized for n URLs.
+func NewDiskURLStorage(path string, n int) (*DiskURLStorage, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DiskURLStorage{
+		path:   path,
+		file:   f,
+		writer: bufio.NewWriter(f),
+		filter: NewBloomFilter(n, bloomFalsePositiveRate),
+	}, nil
+}
+
+// Returns true if a URL string has probably been added.
+func (s *DiskURLStorage) Seen(u string) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.filter.Test(u)
+}
+
+// Adds an URL string to the filter and the file.
+func (s *DiskURLStorage) Add(u string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.filter.Test(u) {
+		return
+	}
+
+	s.filter.Add(u)
+	s.writer.WriteString(u + "\n")
+}
+
+// Iterate reads the URLs from the file, applying the provided function f to each one of them.
+func (s *DiskURLStorage) Iterate(f func(string)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.writer.Flush(); err != nil {
+		return
+	}
+
+	r, err := os.Open(s.path)
+	if err != nil {
+		return
+	}
+	defer r.Close()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStorageLine)
+	for scanner.Scan() {
+		f(scanner.Text())
+	}
+}
+
+// Close closes the store's file.
+func (s *DiskURLStorage) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.file.Close()
+}
+
+// diskRequest is the JSON representation of a RequestMessage in the DiskBacklog.
+type diskRequest struct {
+	URL          string `json:"u"`
+	OriginalURL  string `json:"o,omitempty"`
+	IgnoreDomain bool   `json:"i,omitempty"`
+	Method       Method `json:"m,omitempty"`
+	Depth        int    `json:"d"`
+}
+
+// DiskBacklog is a RequestBacklog that appends the requests to a file and reads them back
+// in the same order. The file is truncated every time the backlog is emptied.
+// The requests' Data is not stored, and requests that can't be written are dropped.
+type DiskBacklog struct {
+	path   string
+	file   *os.File
+	writer *bufio.Writer
+	input  *os.File
+	reader *bufio.Reader
+	offset int64 // Offset in the file of the next request to be read.
+	count  int
+}
+
+// NewDiskBacklog creates the backlog's file.
+func NewDiskBacklog(path string) (*DiskBacklog, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := os.Open(path)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &DiskBacklog{
+		path:   path,
+		file:   f,
+		writer: bufio.NewWriter(f),
+		input:  input,
+		reader: bufio.NewReaderSize(input, 64*1024),
+	}, nil
+}
+
+// Push appends the request to the backlog's file.
+func (b *DiskBacklog) Push(r *RequestMessage) {
+	dr := diskRequest{
+		URL:          r.URL.String(),
+		IgnoreDomain: r.IgnoreDomain,
+		Method:       r.Method,
+		Depth:        r.Depth,
+	}
+
+	if r.OriginalURL != nil {
+		dr.OriginalURL = r.OriginalURL.String()
+	}
+
+	line, err := json.Marshal(dr)
+	if err != nil {
+		return
+	}
+
+	if _, err := b.writer.Write(append(line, '\n')); err != nil {
+		return
+	}
+
+	b.count++
+}
+
+// Pop reads the next request from the backlog's file. It returns nil if the backlog is
+// empty or if the request can't be read.
+func (b *DiskBacklog) Pop() *RequestMessage {
+	if b.count == 0 {
+		return nil
+	}
+
+	if err := b.writer.Flush(); err != nil {
+		return nil
+	}
+
+	line, err := b.reader.ReadBytes('\n')
+	if err != nil {
+		return nil
+	}
+
+	b.offset += int64(len(line))
+	b.count--
+
+	if b.count == 0 {
+		b.reset()
+	}
+
+	r, err := decodeDiskRequest(line)
+	if err != nil {
+		return nil
+	}
+
+	return r
+}
+
+// Len returns the number of requests in the backlog.
+func (b *DiskBacklog) Len() int {
+	return b.count
+}
+
+// Close closes the backlog's file.
+func (b *DiskBacklog) Close() error {
+	return errors.Join(b.file.Close(), b.input.Close())
+}
+
+// reset truncates the file once all the requests have been read, so it doesn't grow
+// during the whole crawl.
+func (b *DiskBacklog) reset() {
+	if err := b.file.Truncate(0); err != nil {
+		return
+	}
+
+	if _, err := b.file.Seek(0, io.SeekStart); err != nil {
+		return
+	}
+
+	if _, err := b.input.Seek(0, io.SeekStart); err != nil {
+		return
+	}
+
+	b.reader.Reset(b.input)
+	b.writer.Reset(b.file)
+	b.offset = 0
+}
+
+// decodeDiskRequest returns the RequestMessage of a line of the backlog's file.
+func decodeDiskRequest(line []byte) (*RequestMessage, error) {
+	var dr diskRequest
+	if err := json.Unmarshal(line, &dr); err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(dr.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &RequestMessage{
+		URL:          u,
+		IgnoreDomain: dr.IgnoreDomain,
+		Method:       dr.Method,
+		Depth:        dr.Depth,
+	}
+
+	if dr.OriginalURL != "" {
+		r.OriginalURL, _ = url.Parse(dr.OriginalURL)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,105 @@
+package crawler_test
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+)
+
+func TestBloomFilter(t *testing.T) {
+	b := crawler.NewBloomFilter(1000, 0.001)
+	for i := 0; i < 1000; i++ {
+		b.Add(fmt.Sprintf("https://example.com/%d", i))
+	}
+
+	for i := 0; i < 1000; i++ {
+		if !b.Test(fmt.Sprintf("https://example.com/%d", i)) {
+			t.Fatalf("Test %d false != true", i)
+		}
+	}
+
+	falsePositives := 0
+	for i := 1000; i < 11000; i++ {
+		if b.Test(fmt.Sprintf("https://example.com/%d", i)) {
+			falsePositives++
+		}
+	}
+
+	if falsePositives > 50 {
+		t.Errorf("False positives %d > 50", falsePositives)
+	}
+}
+
+func TestDiskStorage(t *testing.T) {
+	dir := t.TempDir()
+	s, err := crawler.NewDiskStorage(dir, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	urls := []string{"https://example.com/", "https://example.com/a", "https://example.com/b"}
+	for _, u := range urls {
+		s.URLs.Add(u)
+	}
+	s.URLs.Add(urls[0])
+
+	if !s.URLs.Seen(urls[1]) || s.URLs.Seen("https://example.com/c") {
+		t.Error("Seen returned wrong results")
+	}
+
+	seen := []string{}
+	s.URLs.Iterate(func(u string) {
+		seen = append(seen, u)
+	})
+
+	if len(seen) != len(urls) {
+		t.Errorf("Iterate: %v != %v", seen, urls)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("Close: %d files left", len(entries))
+	}
+}
+
+func TestDiskBacklogQueue(t *testing.T) {
+	backlog, err := crawler.NewDiskBacklog(t.TempDir() + "/queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backlog.Close()
+
+	queue := crawler.NewBacklogQueue(backlog)
+	defer queue.Done()
+
+	original := &url.URL{Scheme: "https", Host: "example.com", Path: "/A"}
+	queue.Push(&crawler.RequestMessage{URL: &url.URL{Scheme: "https", Host: "example.com", Path: "/a"}, OriginalURL: original, Depth: 1})
+	queue.Push(&crawler.RequestMessage{URL: &url.URL{Scheme: "https", Host: "example.com", Path: "/b"}, IgnoreDomain: true, Depth: 2})
+
+	if c := queue.Count(); c != 1 {
+		t.Errorf("Count %d != 1", c)
+	}
+
+	a := queue.Poll()
+	if a.URL.Path != "/a" || a.OriginalURL.String() != original.String() || a.Depth != 1 {
+		t.Errorf("Poll %v", a)
+	}
+
+	b := queue.Poll()
+	if b.URL.Path != "/b" || !b.IgnoreDomain || b.Depth != 2 {
+		t.Errorf("Poll %v", b)
+	}
+
+	// The backlog is truncated once it is empty and new requests are added after it.
+	queue.Push(&crawler.RequestMessage{URL: &url.URL{Scheme: "https", Host: "example.com", Path: "/c"}})
+	if c := queue.Poll(); c.URL.Path != "/c" {
+		t.Errorf("Poll %v", c)
+	}
+}
@@ -0,0 +1,59 @@
+package crawler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// ParseHeaders parses the headers in s, one "Name: value" header per line.
+// Empty lines are ignored.
+func ParseHeaders(s string) (http.Header, error) {
+	h := make(http.Header)
+
+	for n, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+		if !ok || !httpguts.ValidHeaderFieldName(name) {
+			return nil, fmt.Errorf("line %d: invalid header name", n+1)
+		}
+
+		if !httpguts.ValidHeaderFieldValue(value) {
+			return nil, fmt.Errorf("line %d: invalid header value", n+1)
+		}
+
+		h.Add(name, value)
+	}
+
+	return h, nil
+}
+
+// ParseCookies parses the cookies in s, one "name=value" cookie per line.
+// Empty lines are ignored.
+func ParseCookies(s string) ([]*http.Cookie, error) {
+	cookies := []*http.Cookie{}
+
+	for n, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		c, err := http.ParseCookie(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n+1, err)
+		}
+
+		cookies = append(cookies, c...)
+	}
+
+	return cookies, nil
+}
@@ -0,0 +1,54 @@
+package crawler_test
+
+import (
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+)
+
+func TestParseHeaders(t *testing.T) {
+	h, err := crawler.ParseHeaders("X-Bypass-Cdn: secret\n\naccept-language: es-ES, es;q=0.9\nX-Multi: a\nX-Multi: b")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if h.Get("X-Bypass-Cdn") != "secret" {
+		t.Errorf("X-Bypass-Cdn header should be secret, got %q", h.Get("X-Bypass-Cdn"))
+	}
+
+	if h.Get("Accept-Language") != "es-ES, es;q=0.9" {
+		t.Errorf("Accept-Language header should be es-ES, es;q=0.9, got %q", h.Get("Accept-Language"))
+	}
+
+	if len(h.Values("X-Multi")) != 2 {
+		t.Errorf("X-Multi header should have 2 values, got %d", len(h.Values("X-Multi")))
+	}
+
+	for _, s := range []string{"X-Missing-Colon", "Invalid Name: value", ": value"} {
+		if _, err := crawler.ParseHeaders(s); err == nil {
+			t.Errorf("headers %q should return an error", s)
+		}
+	}
+}
+
+func TestParseCookies(t *testing.T) {
+	cookies, err := crawler.ParseCookies("consent=yes\nsession=abc; lang=en")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := map[string]string{"consent": "yes", "session": "abc", "lang": "en"}
+	if len(cookies) != len(expected) {
+		t.Fatalf("expected %d cookies, got %d", len(expected), len(cookies))
+	}
+
+	for _, c := range cookies {
+		if expected[c.Name] != c.Value {
+			t.Errorf("cookie %s should be %q, got %q", c.Name, expected[c.Name], c.Value)
+		}
+	}
+
+	if _, err := crawler.ParseCookies("invalid cookie"); err == nil {
+		t.Error("invalid cookie should return an error")
+	}
+}
@@ -0,0 +1,73 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// DialFunc is the dial function used by an http.Transport to open connections.
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// HostOverrides maps host names to the addresses the client connects to instead of the
+// resolved ones, in the same way as the entries of an /etc/hosts file. The addresses are
+// IPs, optionally with a port that replaces the port of the requested URL.
+type HostOverrides map[string]string
+
+// ParseHostOverrides parses the host overrides in s, one entry per line in the /etc/hosts
+// format: the address followed by one or more host names. Empty lines and comments starting
+// with "#" are ignored.
+func ParseHostOverrides(s string) (HostOverrides, error) {
+	h := make(HostOverrides)
+
+	for n, line := range strings.Split(s, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: missing host name", n+1)
+		}
+
+		ip := fields[0]
+		if host, port, err := net.SplitHostPort(fields[0]); err == nil && port != "" {
+			ip = host
+		}
+
+		if net.ParseIP(ip) == nil {
+			return nil, fmt.Errorf("line %d: invalid IP address", n+1)
+		}
+
+		for _, host := range fields[1:] {
+			h[strings.ToLower(host)] = fields[0]
+		}
+	}
+
+	return h, nil
+}
+
+// DialContext returns a dial function that connects to the overridden address of the hosts
+// using the dial function, the other hosts are dialed as usual. As only the address of the
+// connection changes, the requests keep the original Host header and TLS server name.
+func (h HostOverrides) DialContext(dial DialFunc) DialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return dial(ctx, network, addr)
+		}
+
+		override, ok := h[strings.ToLower(host)]
+		if !ok {
+			return dial(ctx, network, addr)
+		}
+
+		if _, _, err := net.SplitHostPort(override); err == nil {
+			return dial(ctx, network, override)
+		}
+
+		return dial(ctx, network, net.JoinHostPort(override, port))
+	}
+}
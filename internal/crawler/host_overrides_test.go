@@ -0,0 +1,76 @@
+package crawler_test
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+)
+
+func TestParseHostOverrides(t *testing.T) {
+	h, err := crawler.ParseHostOverrides("# staging\n203.0.113.10 example.com WWW.example.com\n\n[::1]:8443 blog.example.com # local")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := map[string]string{
+		"example.com":      "203.0.113.10",
+		"www.example.com":  "203.0.113.10",
+		"blog.example.com": "[::1]:8443",
+	}
+
+	if len(h) != len(expected) {
+		t.Errorf("expected %d host overrides, got %d", len(expected), len(h))
+	}
+
+	for host, addr := range expected {
+		if h[host] != addr {
+			t.Errorf("host %s should be overridden with %s, got %q", host, addr, h[host])
+		}
+	}
+
+	for _, s := range []string{"203.0.113.10", "example.com 203.0.113.10", "203.0.113:80 example.com"} {
+		if _, err := crawler.ParseHostOverrides(s); err == nil {
+			t.Errorf("host overrides %q should return an error", s)
+		}
+	}
+}
+
+// TestHostOverridesDialContext tests the client connects to the overridden address keeping
+// the original Host header and TLS server name.
+func TestHostOverridesDialContext(t *testing.T) {
+	var host, serverName string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+		serverName = r.TLS.ServerName
+	}))
+	defer server.Close()
+
+	h, err := crawler.ParseHostOverrides(server.Listener.Addr().String() + " example.com")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// The test server's certificate is valid for example.com.
+	transport := server.Client().Transport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: transport.TLSClientConfig.RootCAs}
+	transport.DialContext = h.DialContext((&net.Dialer{}).DialContext)
+
+	client := crawler.NewBasicClient(&crawler.ClientOptions{UserAgent: "TEST UA"}, &http.Client{Transport: transport})
+	r, err := client.Get("https://example.com/")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	r.Response.Body.Close()
+
+	if host != "example.com" {
+		t.Errorf("expected Host header example.com, got %q", host)
+	}
+
+	if serverName != "example.com" {
+		t.Errorf("expected TLS server name example.com, got %q", serverName)
+	}
+}
@@ -0,0 +1,123 @@
+package crawler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var ErrLoginFailed = errors.New("login failed")
+
+// LoginOptions holds the details needed to log into a website with a login form.
+type LoginOptions struct {
+	URL       string // URL of the page with the login form.
+	UserField string // Name of the form's username field.
+	PassField string // Name of the form's password field.
+	User      string
+	Pass      string
+}
+
+// FormLogin logs into a website by posting the credentials to its login form. The login page is
+// requested first so the form's action URL and hidden fields, such as CSRF tokens, can be sent
+// along with the credentials. The session cookies are kept in the client's cookie jar.
+func (c *BasicClient) FormLogin(o *LoginOptions) error {
+	r, err := c.Get(o.URL)
+	if err != nil {
+		return err
+	}
+	defer r.Response.Body.Close()
+
+	if r.Response.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%w: login page status code %d", ErrLoginFailed, r.Response.StatusCode)
+	}
+
+	loginURL, err := url.Parse(o.URL)
+	if err != nil {
+		return err
+	}
+
+	doc, err := html.Parse(r.Response.Body)
+	if err != nil {
+		return err
+	}
+
+	action, values := findLoginForm(doc, o.PassField)
+	if action == nil {
+		return fmt.Errorf("%w: login form not found", ErrLoginFailed)
+	}
+
+	values.Set(o.UserField, o.User)
+	values.Set(o.PassField, o.Pass)
+
+	r, err = c.Post(loginURL.ResolveReference(action).String(), values)
+	if err != nil {
+		return err
+	}
+	defer r.Response.Body.Close()
+
+	if r.Response.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%w: status code %d", ErrLoginFailed, r.Response.StatusCode)
+	}
+
+	return nil
+}
+
+// findLoginForm returns the action URL and the hidden field values of the first form containing
+// the password field. The action URL is nil if there's no such form.
+func findLoginForm(n *html.Node, passField string) (*url.URL, url.Values) {
+	if n.Type == html.ElementNode && n.Data == "form" {
+		values := url.Values{}
+		found := false
+
+		var inputs func(*html.Node)
+		inputs = func(n *html.Node) {
+			if n.Type == html.ElementNode && n.Data == "input" {
+				name := getAttr(n, "name")
+				if name == passField {
+					found = true
+				}
+
+				if strings.EqualFold(getAttr(n, "type"), "hidden") && name != "" {
+					values.Set(name, getAttr(n, "value"))
+				}
+			}
+
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				inputs(c)
+			}
+		}
+		inputs(n)
+
+		if found {
+			action, err := url.Parse(getAttr(n, "action"))
+			if err != nil {
+				return nil, nil
+			}
+
+			return action, values
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if action, values := findLoginForm(c, passField); action != nil {
+			return action, values
+		}
+	}
+
+	return nil, nil
+}
+
+// getAttr returns the value of a node's attribute or an empty string if it doesn't exist.
+func getAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,98 @@
+package crawler_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+)
+
+func newLoginServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body>
+			<form action="/search"><input name="q"></form>
+			<form method="post" action="/session">
+				<input type="hidden" name="csrf" value="token">
+				<input name="email"><input type="password" name="pwd">
+			</form>
+		</body></html>`)
+	})
+	mux.HandleFunc("/session", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.FormValue("csrf") != "token" ||
+			r.FormValue("email") != "user" || r.FormValue("pwd") != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "ok", Path: "/"})
+		http.Redirect(w, r, "/", http.StatusFound)
+	})
+	mux.HandleFunc("/private", func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie("session"); err != nil || c.Value != "ok" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func newJarClient() *http.Client {
+	jar, _ := cookiejar.New(nil)
+	return &http.Client{
+		Jar: jar,
+		CheckRedirect: func(r *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// TestFormLogin tests the session cookie is kept after logging in with the login form.
+func TestFormLogin(t *testing.T) {
+	ts := newLoginServer()
+	defer ts.Close()
+
+	client := crawler.NewBasicClient(&crawler.ClientOptions{UserAgent: "TEST_UA"}, newJarClient())
+	err := client.FormLogin(&crawler.LoginOptions{
+		URL:       ts.URL + "/login",
+		UserField: "email",
+		PassField: "pwd",
+		User:      "user",
+		Pass:      "pass",
+	})
+	if err != nil {
+		t.Fatalf("unexpected login error %v", err)
+	}
+
+	r, err := client.Get(ts.URL + "/private")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if r.Response.StatusCode != http.StatusOK {
+		t.Errorf("expected status code 200 after login, got %d", r.Response.StatusCode)
+	}
+}
+
+// TestFormLoginErrors tests wrong credentials and missing login forms return ErrLoginFailed.
+func TestFormLoginErrors(t *testing.T) {
+	ts := newLoginServer()
+	defer ts.Close()
+
+	table := []*crawler.LoginOptions{
+		{URL: ts.URL + "/login", UserField: "email", PassField: "pwd", User: "user", Pass: "wrong"},
+		{URL: ts.URL + "/login", UserField: "email", PassField: "password", User: "user", Pass: "pass"},
+		{URL: ts.URL + "/missing", UserField: "email", PassField: "pwd", User: "user", Pass: "pass"},
+	}
+
+	for _, o := range table {
+		client := crawler.NewBasicClient(&crawler.ClientOptions{UserAgent: "TEST_UA"}, newJarClient())
+		if err := client.FormLogin(o); !errors.Is(err, crawler.ErrLoginFailed) {
+			t.Errorf("%+v: expected ErrLoginFailed, got %v", o, err)
+		}
+	}
+}
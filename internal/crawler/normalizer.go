@@ -0,0 +1,161 @@
+package crawler
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Trailing slash normalization modes.
+const (
+	TrailingSlashAdd    = "add"    // Add the trailing slash to the paths without a file extension.
+	TrailingSlashRemove = "remove" // Remove the trailing slash from the paths.
+)
+
+// Normalizer rewrites URLs before they are checked and stored, so URL variants
+// that point to the same page are only crawled once.
+type Normalizer struct {
+	stripParams   []*regexp.Regexp
+	sortParams    bool
+	dropFragment  bool
+	lowercaseHost bool
+	trailingSlash string
+}
+
+type NormalizerOptions struct {
+	StripParams   []string // Names of the query parameters to remove, "*" can be used as a wildcard.
+	SortParams    bool     // Sort the query parameters by name.
+	DropFragment  bool     // Remove the URL's fragment.
+	LowercaseHost bool     // Lowercase the URL's host.
+	TrailingSlash string   // Add or remove the path's trailing slash, see TrailingSlashAdd and TrailingSlashRemove.
+}
+
+// NewNormalizer returns a Normalizer with the specified options.
+// Parameter names are matched case-insensitively.
+func NewNormalizer(o NormalizerOptions) *Normalizer {
+	n := &Normalizer{
+		sortParams:    o.SortParams,
+		dropFragment:  o.DropFragment,
+		lowercaseHost: o.LowercaseHost,
+		trailingSlash: o.TrailingSlash,
+	}
+
+	for _, p := range o.StripParams {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		re, err := globToRegexp(strings.ToLower(p))
+		if err != nil {
+			continue
+		}
+
+		n.stripParams = append(n.stripParams, re)
+	}
+
+	return n
+}
+
+// Normalize returns a normalized copy of the URL. The original URL is not modified.
+// A nil Normalizer returns the URL unchanged.
+func (n *Normalizer) Normalize(u *url.URL) *url.URL {
+	if n == nil {
+		return u
+	}
+
+	nu := *u
+
+	if n.lowercaseHost {
+		nu.Host = strings.ToLower(nu.Host)
+	}
+
+	if n.dropFragment {
+		nu.Fragment = ""
+		nu.RawFragment = ""
+	}
+
+	if n.trailingSlash != "" {
+		n.normalizeTrailingSlash(&nu)
+	}
+
+	if nu.RawQuery != "" && (n.sortParams || len(n.stripParams) > 0) {
+		nu.RawQuery = n.normalizeQuery(nu.RawQuery)
+		nu.ForceQuery = false
+	}
+
+	return &nu
+}
+
+// normalizeTrailingSlash adds or removes the trailing slash of the URL's path. The root path
+// is not changed, and the slash is only added to the paths whose last segment doesn't have
+// a file extension, such as "/blog" but not "/style.css".
+func (n *Normalizer) normalizeTrailingSlash(u *url.URL) {
+	if u.Path == "" || u.Path == "/" {
+		return
+	}
+
+	switch n.trailingSlash {
+	case TrailingSlashAdd:
+		if !strings.HasSuffix(u.Path, "/") && path.Ext(u.Path) == "" {
+			u.Path += "/"
+			if u.RawPath != "" {
+				u.RawPath += "/"
+			}
+		}
+	case TrailingSlashRemove:
+		u.Path = strings.TrimRight(u.Path, "/")
+		u.RawPath = strings.TrimRight(u.RawPath, "/")
+		if u.Path == "" {
+			u.Path = "/"
+		}
+	}
+}
+
+// normalizeQuery removes and sorts the query parameters keeping their original encoding.
+func (n *Normalizer) normalizeQuery(q string) string {
+	params := []string{}
+	for _, p := range strings.Split(q, "&") {
+		if p == "" {
+			continue
+		}
+
+		if n.isStripped(paramName(p)) {
+			continue
+		}
+
+		params = append(params, p)
+	}
+
+	if n.sortParams {
+		sort.SliceStable(params, func(i, j int) bool {
+			return paramName(params[i]) < paramName(params[j])
+		})
+	}
+
+	return strings.Join(params, "&")
+}
+
+// isStripped returns true if the parameter name matches any of the parameters to be removed.
+func (n *Normalizer) isStripped(name string) bool {
+	name = strings.ToLower(name)
+	for _, re := range n.stripParams {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// paramName returns the unescaped name of a "name=value" query parameter.
+func paramName(p string) string {
+	name, _, _ := strings.Cut(p, "=")
+	if unescaped, err := url.QueryUnescape(name); err == nil {
+		return unescaped
+	}
+
+	return name
+}
@@ -0,0 +1,90 @@
+package crawler_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+)
+
+func TestNormalize(t *testing.T) {
+	n := crawler.NewNormalizer(crawler.NormalizerOptions{
+		StripParams:   []string{"utm_*", "sessionid"},
+		SortParams:    true,
+		DropFragment:  true,
+		LowercaseHost: true,
+	})
+
+	table := map[string]string{
+		"https://Example.COM/Path":                        "https://example.com/Path",
+		"https://example.com/?utm_source=x&utm_medium=y":  "https://example.com/",
+		"https://example.com/?b=2&a=1&SessionId=3":        "https://example.com/?a=1&b=2",
+		"https://example.com/?q=a%20b&utm_campaign=z#top": "https://example.com/?q=a%20b",
+		"https://example.com/page#section":                "https://example.com/page",
+		"https://example.com/?a=2&a=1":                    "https://example.com/?a=2&a=1",
+		"https://example.com/?utm=keep&xutm_source=keep":  "https://example.com/?utm=keep&xutm_source=keep",
+	}
+
+	for in, expected := range table {
+		u, err := url.Parse(in)
+		if err != nil {
+			t.Fatalf("url parse error %v", err)
+		}
+
+		if n := n.Normalize(u).String(); n != expected {
+			t.Errorf("%s normalized to %s, expected %s", in, n, expected)
+		}
+
+		if u.String() != in {
+			t.Errorf("original URL %s was modified", in)
+		}
+	}
+}
+
+func TestNormalizeDisabled(t *testing.T) {
+	var n *crawler.Normalizer
+	u, err := url.Parse("https://Example.com/?b=1&a=2#top")
+	if err != nil {
+		t.Fatalf("url parse error %v", err)
+	}
+
+	if n.Normalize(u).String() != u.String() {
+		t.Errorf("nil normalizer should not modify the URL")
+	}
+
+	n = crawler.NewNormalizer(crawler.NormalizerOptions{})
+	if n.Normalize(u).String() != u.String() {
+		t.Errorf("normalizer without options should not modify the URL")
+	}
+}
+
+func TestNormalizeTrailingSlash(t *testing.T) {
+	table := []struct {
+		mode     string
+		in       string
+		expected string
+	}{
+		{crawler.TrailingSlashAdd, "https://example.com/blog", "https://example.com/blog/"},
+		{crawler.TrailingSlashAdd, "https://example.com/blog/?p=2", "https://example.com/blog/?p=2"},
+		{crawler.TrailingSlashAdd, "https://example.com/blog?p=2", "https://example.com/blog/?p=2"},
+		{crawler.TrailingSlashAdd, "https://example.com/style.css", "https://example.com/style.css"},
+		{crawler.TrailingSlashAdd, "https://example.com", "https://example.com"},
+		{crawler.TrailingSlashAdd, "https://example.com/a%2Fb", "https://example.com/a%2Fb/"},
+		{crawler.TrailingSlashRemove, "https://example.com/blog/", "https://example.com/blog"},
+		{crawler.TrailingSlashRemove, "https://example.com/blog//?p=2", "https://example.com/blog?p=2"},
+		{crawler.TrailingSlashRemove, "https://example.com/", "https://example.com/"},
+		{"", "https://example.com/blog/", "https://example.com/blog/"},
+	}
+
+	for _, v := range table {
+		u, err := url.Parse(v.in)
+		if err != nil {
+			t.Fatalf("url parse error %v", err)
+		}
+
+		n := crawler.NewNormalizer(crawler.NormalizerOptions{TrailingSlash: v.mode})
+		if n := n.Normalize(u).String(); n != v.expected {
+			t.Errorf("%s with mode %q normalized to %s, expected %s", v.in, v.mode, n, v.expected)
+		}
+	}
+}
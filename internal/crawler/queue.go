@@ -1,22 +1,63 @@
 package crawler
 
+import "context"
+
+// RequestBacklog stores the requests waiting in the queue in FIFO order.
+type RequestBacklog interface {
+	Push(r *RequestMessage)
+	Pop() *RequestMessage // Pop returns nil if the backlog is empty.
+	Len() int
+}
+
+// memoryBacklog is a RequestBacklog that keeps the requests in a slice.
+type memoryBacklog struct {
+	requests []*RequestMessage
+}
+
+func (b *memoryBacklog) Push(r *RequestMessage) {
+	b.requests = append(b.requests, r)
+}
+
+func (b *memoryBacklog) Pop() *RequestMessage {
+	if len(b.requests) == 0 {
+		return nil
+	}
+
+	r := b.requests[0]
+	b.requests = b.requests[1:]
+
+	return r
+}
+
+func (b *memoryBacklog) Len() int {
+	return len(b.requests)
+}
+
 type Queue struct {
-	in     chan *RequestMessage
-	out    chan *RequestMessage
-	ack    chan string
-	count  chan int
-	active chan bool
-	done   chan struct{}
+	in      chan *RequestMessage
+	out     chan *RequestMessage
+	ack     chan string
+	count   chan int
+	active  chan bool
+	done    chan struct{}
+	backlog RequestBacklog
 }
 
+// NewQueue returns a Queue that keeps the pending requests in memory.
 func NewQueue() *Queue {
+	return NewBacklogQueue(&memoryBacklog{})
+}
+
+// NewBacklogQueue returns a Queue that keeps the pending requests in the backlog.
+func NewBacklogQueue(backlog RequestBacklog) *Queue {
 	q := Queue{
-		in:     make(chan *RequestMessage),
-		out:    make(chan *RequestMessage),
-		ack:    make(chan string),
-		count:  make(chan int),
-		active: make(chan bool),
-		done:   make(chan struct{}),
+		in:      make(chan *RequestMessage),
+		out:     make(chan *RequestMessage),
+		ack:     make(chan string),
+		count:   make(chan int),
+		active:  make(chan bool),
+		done:    make(chan struct{}),
+		backlog: backlog,
 	}
 
 	go q.manage()
@@ -35,17 +76,17 @@ func (q *Queue) manage() {
 		close(q.done)
 	}()
 
-	queue := []*RequestMessage{}
-	active := make(map[string]bool)
+	active := make(map[string]*RequestMessage)
 
 	var first *RequestMessage
 	var out chan *RequestMessage
 
 	for {
-		if first == nil && len(queue) > 0 {
-			first = queue[0]
-			active[first.URL.String()] = true
-			queue = queue[1:]
+		if first == nil && q.backlog.Len() > 0 {
+			first = q.backlog.Pop()
+			if first != nil {
+				active[first.URL.String()] = first
+			}
 		}
 
 		if first == nil {
@@ -57,10 +98,10 @@ func (q *Queue) manage() {
 		select {
 		case <-q.done:
 			return
-		case q.count <- len(queue):
-		case q.active <- (len(active) > 0 || len(queue) > 0):
+		case q.count <- q.backlog.Len():
+		case q.active <- (len(active) > 0 || q.backlog.Len() > 0):
 		case v := <-q.in:
-			queue = append(queue, v)
+			q.backlog.Push(v)
 		case out <- first:
 			first = nil
 		case v := <-q.ack:
@@ -79,6 +120,17 @@ func (q *Queue) Poll() *RequestMessage {
 	return <-q.out
 }
 
+// PollContext returns the first element in the queue, or false if the context is done
+// before there is an element to return.
+func (q *Queue) PollContext(ctx context.Context) (*RequestMessage, bool) {
+	select {
+	case <-ctx.Done():
+		return nil, false
+	case v := <-q.out:
+		return v, true
+	}
+}
+
 // Acknowledges a message has been processed.
 func (q *Queue) Ack(s string) {
 	q.ack <- s
@@ -0,0 +1,117 @@
+package crawler
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket rate limiter that keeps a separate bucket for each host,
+// so the requests per second limit is enforced on every host independently.
+// A host can also have its own minimum delay between requests, such as the robots.txt
+// Crawl-delay, in which case the slowest of both rates is used. Hosts can be slowed down
+// further, for instance if they respond with 429 status codes.
+type RateLimiter struct {
+	rate      float64
+	buckets   map[string]*bucket
+	delays    map[string]time.Duration
+	slowdowns map[string]time.Duration
+	lock      *sync.Mutex
+}
+
+const (
+	// Delay added to a host the first time it is slowed down.
+	minSlowdown = 500 * time.Millisecond
+
+	// Max delay between requests to a host that has been slowed down.
+	maxSlowdown = 30 * time.Second
+)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a RateLimiter that allows up to rps requests per second on each host.
+// A rate of zero or less disables the limiter unless a delay is set for the host.
+func NewRateLimiter(rps float64) *RateLimiter {
+	return &RateLimiter{
+		rate:      rps,
+		buckets:   make(map[string]*bucket),
+		delays:    make(map[string]time.Duration),
+		slowdowns: make(map[string]time.Duration),
+		lock:      &sync.Mutex{},
+	}
+}
+
+// SetDelay sets a minimum delay between requests to the host.
+func (l *RateLimiter) SetDelay(host string, d time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.delays[host] = d
+}
+
+// Slowdown doubles the minimum delay between requests to the host, up to a max delay.
+// The host's bucket is emptied so the next request waits for the new delay. The slowdown
+// is kept until the limiter is discarded, even if a new delay is set.
+func (l *RateLimiter) Slowdown(host string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	d := max(l.slowdowns[host]*2, l.delays[host]*2, minSlowdown)
+	l.slowdowns[host] = min(d, maxSlowdown)
+	l.buckets[host] = &bucket{last: time.Now()}
+}
+
+// Wait blocks until a new request to the host is allowed or the context is cancelled,
+// in which case it returns the context's error.
+func (l *RateLimiter) Wait(ctx context.Context, host string) error {
+	d := l.reserve(host, time.Now())
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// reserve takes a token from the host's bucket and returns how long the caller must wait
+// before making the request. The bucket holds a single token so requests are not bursted.
+func (l *RateLimiter) reserve(host string, now time.Time) time.Duration {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	rate := l.rate
+	if d := max(l.delays[host], l.slowdowns[host]); d > 0 && (rate <= 0 || 1/d.Seconds() < rate) {
+		rate = 1 / d.Seconds()
+	}
+
+	if rate <= 0 {
+		return 0
+	}
+
+	b, ok := l.buckets[host]
+	if !ok {
+		b = &bucket{tokens: 1, last: now}
+		l.buckets[host] = b
+	}
+
+	b.tokens = math.Min(1, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / rate * float64(time.Second))
+}
@@ -0,0 +1,96 @@
+package crawler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := crawler.NewRateLimiter(20)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := l.Wait(ctx, "example.com"); err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+	}
+
+	// The first request is not delayed, the other four wait 50ms each.
+	elapsed := time.Since(start)
+	if elapsed < 190*time.Millisecond {
+		t.Errorf("Expected at least 200ms elapsed, got %v", elapsed)
+	}
+
+	// A different host has its own bucket.
+	start = time.Now()
+	if err := l.Wait(ctx, "other.example.com"); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("Expected no wait on a different host, got %v", elapsed)
+	}
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	l := crawler.NewRateLimiter(0)
+
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		l.Wait(context.Background(), "example.com")
+	}
+
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("Expected no wait with a disabled limiter, got %v", elapsed)
+	}
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	l := crawler.NewRateLimiter(0.1)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	l.Wait(ctx, "example.com")
+	cancel()
+
+	if err := l.Wait(ctx, "example.com"); err == nil {
+		t.Error("Expected an error after cancelling the context")
+	}
+}
+
+func TestRateLimiterDelay(t *testing.T) {
+	l := crawler.NewRateLimiter(0)
+	l.SetDelay("example.com", 100*time.Millisecond)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		l.Wait(ctx, "example.com")
+	}
+
+	// The host's delay is used even with the limiter disabled.
+	if elapsed := time.Since(start); elapsed < 190*time.Millisecond {
+		t.Errorf("Expected at least 200ms elapsed, got %v", elapsed)
+	}
+}
+
+func TestRateLimiterSlowdown(t *testing.T) {
+	l := crawler.NewRateLimiter(0)
+	l.SetDelay("example.com", 50*time.Millisecond)
+	l.Slowdown("example.com")
+	l.SetDelay("example.com", 50*time.Millisecond)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		l.Wait(ctx, "example.com")
+	}
+
+	// The slowdown doubles the host's delay and it is kept after setting the delay again.
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected at least 100ms elapsed, got %v", elapsed)
+	}
+}
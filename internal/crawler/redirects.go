@@ -0,0 +1,172 @@
+package crawler
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// Max number of hops kept in the crawler's hop cache.
+const maxCachedHops = 10000
+
+// RedirectHop is a single request of a redirect chain.
+type RedirectHop struct {
+	URL        string
+	StatusCode int    // Status code of the hop's response, -1 if the request failed.
+	Location   string // Absolute redirect URL, empty if the hop doesn't redirect.
+	Blocked    bool   // The hop is blocked by the robots.txt so it was not requested.
+}
+
+// hopCache keeps the responses of the URLs requested in the crawl, so the redirect hops
+// to URLs that have already been requested don't need to be requested again.
+type hopCache struct {
+	hops map[string]RedirectHop
+	lock *sync.RWMutex
+}
+
+func newHopCache() *hopCache {
+	return &hopCache{
+		hops: make(map[string]RedirectHop),
+		lock: &sync.RWMutex{},
+	}
+}
+
+// add adds a hop to the cache unless the cache is full.
+func (h *hopCache) add(hop RedirectHop) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if len(h.hops) < maxCachedHops {
+		h.hops[hop.URL] = hop
+	}
+}
+
+// get returns the cached hop of the URL and true if it exists.
+func (h *hopCache) get(urlStr string) (RedirectHop, bool) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	hop, ok := h.hops[urlStr]
+	return hop, ok
+}
+
+// followRedirects follows the redirects of a response up to the MaxRedirects option and returns
+// the complete redirect chain, starting with the response's own URL. The chain ends with the first
+// hop that doesn't redirect, a blocked or failed request, a redirect loop or once the limit is
+// reached. It also ends with the first hop to a host that is not allowed in the crawl, so the
+// redirects of external hosts are not followed. It returns nil if the response is not a redirect.
+func (c *Crawler) followRedirects(u *url.URL, resp *http.Response) []RedirectHop {
+	if c.options.MaxRedirects <= 0 || !isRedirect(resp.StatusCode) {
+		return nil
+	}
+
+	hop := newRedirectHop(u.String(), resp)
+	hops := []RedirectHop{}
+	seen := make(map[string]bool)
+
+	for {
+		hops = append(hops, hop)
+		seen[hop.URL] = true
+
+		if hop.Location == "" || seen[hop.Location] || len(hops) > c.options.MaxRedirects {
+			return hops
+		}
+
+		if u, err := url.Parse(hop.URL); err != nil || !c.DomainIsAllowed(u.Host) {
+			return hops
+		}
+
+		hop = c.requestHop(hop.Location)
+	}
+}
+
+// requestHop returns the hop of a redirect chain's URL. The URLs are requested the same way as
+// the crawled URLs, waiting for the host's rate limiter and retrying the failed requests. Only the
+// response's status code and Location header are needed, so the response body is not read. The
+// URLs blocked by the robots.txt are not requested and the cached hops are not requested again.
+func (c *Crawler) requestHop(urlStr string) RedirectHop {
+	if hop, ok := c.hops.get(urlStr); ok {
+		return hop
+	}
+
+	hop := RedirectHop{URL: urlStr, StatusCode: -1}
+
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return hop
+	}
+
+	// The hops the client can't request end the chain as failed requests, without
+	// waiting for the retries of a request that can't succeed.
+	if !c.clientServes(u) {
+		return hop
+	}
+
+	if !c.options.IgnoreRobotsTxt && c.robotsChecker.IsBlocked(u) {
+		hop.StatusCode = 0
+		hop.Blocked = true
+		return hop
+	}
+
+	if err := c.sleep(c.randomDelay() + c.throttle.Delay()); err != nil {
+		return hop
+	}
+
+	c.rateLimiter.SetDelay(u.Host, c.crawlDelay(u))
+	if err := c.rateLimiter.Wait(c.context, u.Host); err != nil {
+		return hop
+	}
+
+	r, _, err := c.request(&RequestMessage{URL: u, Method: GET})
+	if err == nil {
+		r.Response.Body.Close()
+		hop = newRedirectHop(urlStr, r.Response)
+	}
+
+	if c.context.Err() == nil {
+		c.hops.add(hop)
+	}
+
+	return hop
+}
+
+// newRedirectHop returns the redirect hop of an URL's response.
+func newRedirectHop(urlStr string, resp *http.Response) RedirectHop {
+	location, _ := redirectLocation(urlStr, resp)
+
+	return RedirectHop{
+		URL:        urlStr,
+		StatusCode: resp.StatusCode,
+		Location:   location,
+	}
+}
+
+// redirectLocation returns the absolute URL in the Location header of a redirect response.
+// It returns false if the response is not a redirect or the Location header is not valid.
+func redirectLocation(urlStr string, resp *http.Response) (string, bool) {
+	if !isRedirect(resp.StatusCode) {
+		return "", false
+	}
+
+	base, err := url.Parse(urlStr)
+	if err != nil {
+		return "", false
+	}
+
+	l := resp.Header.Get("Location")
+	if l == "" {
+		return "", false
+	}
+
+	location, err := base.Parse(l)
+	if err != nil {
+		return "", false
+	}
+
+	return location.String(), true
+}
+
+// isRedirect returns true if the status code is in the 30x range.
+func isRedirect(statusCode int) bool {
+	return statusCode >= 300 && statusCode < 400
+}
@@ -0,0 +1,198 @@
+package crawler_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+)
+
+// newRedirectServer returns a test server with redirect chains and its robots.txt file.
+// The requests to each path are counted in the requests map.
+func newRedirectServer(robots string, requests map[string]int) *httptest.Server {
+	lock := &sync.Mutex{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, robots)
+	})
+	mux.HandleFunc("/a", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/b", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/b", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/c", http.StatusFound)
+	})
+	mux.HandleFunc("/c", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/to", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Query().Get("url"), http.StatusFound)
+	})
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests[r.URL.Path]++
+		lock.Unlock()
+
+		mux.ServeHTTP(w, r)
+	}))
+}
+
+// crawlRedirects crawls the URLs with a single worker and returns the redirect chains of the responses.
+func crawlRedirects(t *testing.T, ts *httptest.Server, maxRedirects int, paths ...string) map[string][]crawler.RedirectHop {
+	client := crawler.NewBasicClient(&crawler.ClientOptions{UserAgent: "TEST_UA"}, newJarClient())
+	c := crawler.NewCrawler(mustParse(t, ts.URL+paths[0]), &crawler.Options{CrawlLimit: 10, Workers: 1, MaxRedirects: maxRedirects}, client)
+
+	chains := make(map[string][]crawler.RedirectHop)
+	c.OnResponse(func(r *crawler.ResponseMessage) {
+		if r.Response != nil {
+			r.Response.Body.Close()
+		}
+
+		chains[r.URL.String()] = r.Redirects
+	})
+
+	for _, p := range paths {
+		c.AddRequest(&crawler.RequestMessage{URL: mustParse(t, ts.URL+p)})
+	}
+
+	c.Start()
+
+	return chains
+}
+
+// TestRedirectChain tests the complete redirect chain is added to the response.
+func TestRedirectChain(t *testing.T) {
+	ts := newRedirectServer("", make(map[string]int))
+	defer ts.Close()
+
+	chains := crawlRedirects(t, ts, 10, "/a", "/c")
+
+	expected := []crawler.RedirectHop{
+		{URL: ts.URL + "/a", StatusCode: http.StatusMovedPermanently, Location: ts.URL + "/b"},
+		{URL: ts.URL + "/b", StatusCode: http.StatusFound, Location: ts.URL + "/c"},
+		{URL: ts.URL + "/c", StatusCode: http.StatusOK},
+	}
+
+	chain := chains[ts.URL+"/a"]
+	if len(chain) != len(expected) {
+		t.Fatalf("expected %d hops, got %d: %+v", len(expected), len(chain), chain)
+	}
+
+	for i, h := range chain {
+		if h != expected[i] {
+			t.Errorf("hop %d: expected %+v got %+v", i, expected[i], h)
+		}
+	}
+
+	if chain := chains[ts.URL+"/c"]; chain != nil {
+		t.Errorf("expected no redirect chain, got %+v", chain)
+	}
+}
+
+// TestRedirectChainLimits tests the redirect chains stop at loops and at the MaxRedirects limit.
+func TestRedirectChainLimits(t *testing.T) {
+	ts := newRedirectServer("", make(map[string]int))
+	defer ts.Close()
+
+	table := []struct {
+		path         string
+		maxRedirects int
+		hops         int
+	}{
+		{"/loop", 10, 1},
+		{"/a", 1, 2},
+		{"/a", 0, 0},
+	}
+
+	for _, tt := range table {
+		chains := crawlRedirects(t, ts, tt.maxRedirects, tt.path)
+
+		if chain := chains[ts.URL+tt.path]; len(chain) != tt.hops {
+			t.Errorf("%s with %d max redirects: expected %d hops, got %d", tt.path, tt.maxRedirects, tt.hops, len(chain))
+		}
+	}
+}
+
+// TestRedirectChainBlocked tests the hops blocked by the robots.txt are not requested.
+func TestRedirectChainBlocked(t *testing.T) {
+	requests := make(map[string]int)
+	ts := newRedirectServer("User-agent: *\nDisallow: /c\n", requests)
+	defer ts.Close()
+
+	chains := crawlRedirects(t, ts, 10, "/a")
+
+	chain := chains[ts.URL+"/a"]
+	if len(chain) != 3 || !chain[2].Blocked {
+		t.Fatalf("expected the last hop to be blocked, got %+v", chain)
+	}
+
+	if requests["/c"] != 0 {
+		t.Errorf("expected the blocked hop not to be requested, got %d requests", requests["/c"])
+	}
+}
+
+// TestRedirectChainRequests tests each hop is requested once, reusing the responses of the
+// URLs that have already been requested in the crawl.
+func TestRedirectChainRequests(t *testing.T) {
+	requests := make(map[string]int)
+	ts := newRedirectServer("", requests)
+	defer ts.Close()
+
+	chains := crawlRedirects(t, ts, 10, "/c", "/a", "/b")
+
+	if chain := chains[ts.URL+"/b"]; len(chain) != 2 {
+		t.Errorf("expected 2 hops in the /b chain, got %+v", chain)
+	}
+
+	expected := map[string]int{"/a": 1, "/b": 2, "/c": 1}
+	for path, n := range expected {
+		if requests[path] != n {
+			t.Errorf("%s: expected %d requests, got %d", path, n, requests[path])
+		}
+	}
+}
+
+// TestRedirectChainExternal tests the redirects are followed up to the first hop to an
+// external host, and that the hops are requested with GET requests.
+func TestRedirectChainExternal(t *testing.T) {
+	external := make(map[string]int)
+	ext := newRedirectServer("", external)
+	defer ext.Close()
+
+	methods := make(map[string]int)
+	handler := ext.Config.Handler
+	ext.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/robots.txt" {
+			methods[r.Method]++
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+
+	ts := newRedirectServer("", make(map[string]int))
+	defer ts.Close()
+
+	path := "/to?url=" + url.QueryEscape(ext.URL+"/a")
+	chains := crawlRedirects(t, ts, 10, path)
+
+	chain := chains[ts.URL+path]
+	if len(chain) != 2 || chain[1].URL != ext.URL+"/a" || chain[1].StatusCode != http.StatusMovedPermanently {
+		t.Fatalf("expected the chain to end at the external hop, got %+v", chain)
+	}
+
+	if external["/a"] != 1 || external["/b"] != 0 {
+		t.Errorf("expected only the first external hop to be requested, got %v", external)
+	}
+
+	if methods[http.MethodGet] != 1 || len(methods) != 1 {
+		t.Errorf("expected the external hop to be requested with GET, got %v", methods)
+	}
+}
@@ -0,0 +1,129 @@
+package crawler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// Default delay before the first retry, it is doubled on each following attempt.
+	defaultRetryBackoff = time.Second
+
+	// Max delay before a retry, including the delays requested with the Retry-After header.
+	maxRetryDelay = time.Minute
+)
+
+// request makes the HTTP request of the request message, retrying it if the request fails or the
+// response has a transient error status code, up to the RetryAttempts option. The delay before each
+// retry grows exponentially unless the response has a Retry-After header, and the host's rate is
+// slowed down when it responds with a 429 status code. It returns the last response along with the
+// number of attempts made.
+func (c *Crawler) request(rm *RequestMessage) (*ClientResponse, int, error) {
+	attempts := max(c.options.RetryAttempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		var r *ClientResponse
+		var err error
+
+		switch rm.Method {
+		case HEAD:
+			r, err = c.client.Head(rm.URL.String())
+		default:
+			r, err = c.client.Get(rm.URL.String())
+		}
+
+		if err == nil && r.Response.StatusCode == http.StatusTooManyRequests {
+			c.rateLimiter.Slowdown(rm.URL.Host)
+		}
+
+		if attempt >= attempts || !isRetryable(r, err) {
+			return r, attempt, err
+		}
+
+		delay := c.retryBackoff(attempt)
+		if err == nil {
+			if d, ok := retryAfter(r.Response, time.Now()); ok {
+				delay = d
+			}
+
+			r.Response.Body.Close()
+		}
+
+		if err := c.sleep(delay); err != nil {
+			return nil, attempt, err
+		}
+
+		if err := c.rateLimiter.Wait(c.context, rm.URL.Host); err != nil {
+			return nil, attempt, err
+		}
+	}
+}
+
+// retryBackoff returns the delay before retrying a request after the given number of attempts.
+func (c *Crawler) retryBackoff(attempt int) time.Duration {
+	backoff := c.options.RetryBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+
+	d := backoff << (attempt - 1)
+	if d <= 0 || d > maxRetryDelay {
+		return maxRetryDelay
+	}
+
+	return d
+}
+
+// sleep blocks for the specified duration or until the crawler's context is cancelled,
+// in which case it returns the context's error.
+func (c *Crawler) sleep(d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-c.context.Done():
+		return c.context.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// isRetryable returns true if the request failed or if the response has a status code
+// that is usually caused by a transient error.
+func isRetryable(r *ClientResponse, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch r.Response.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// retryAfter returns the delay requested in the response's Retry-After header, which can be
+// either a number of seconds or an HTTP date. The delay is capped at the max retry delay.
+// It returns false if the header is missing or not valid.
+func retryAfter(r *http.Response, now time.Time) (time.Duration, bool) {
+	v := r.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	var d time.Duration
+	if seconds, err := strconv.Atoi(v); err == nil {
+		d = time.Duration(seconds) * time.Second
+	} else if t, err := http.ParseTime(v); err == nil {
+		d = t.Sub(now)
+	} else {
+		return 0, false
+	}
+
+	return min(max(d, 0), maxRetryDelay), true
+}
@@ -0,0 +1,109 @@
+package crawler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+)
+
+// TestRetry tests the requests are retried on transient errors up to the RetryAttempts option,
+// and that the number of attempts is added to the response message.
+func TestRetry(t *testing.T) {
+	lock := &sync.Mutex{}
+	requests := make(map[string]int)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests[r.URL.Path]++
+		n := requests[r.URL.Path]
+		lock.Unlock()
+
+		switch {
+		case r.URL.Path == "/flaky" && n < 3:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.URL.Path == "/limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case r.URL.Path == "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	client := crawler.NewBasicClient(&crawler.ClientOptions{UserAgent: "TEST_UA"}, newJarClient())
+	c := crawler.NewCrawler(mustParse(t, ts.URL+"/flaky"), &crawler.Options{
+		CrawlLimit:    10,
+		RetryAttempts: 3,
+		RetryBackoff:  time.Millisecond,
+	}, client)
+
+	type result struct {
+		statusCode int
+		attempts   int
+	}
+	results := make(map[string]result)
+	c.OnResponse(func(r *crawler.ResponseMessage) {
+		results[r.URL.Path] = result{statusCode: r.Response.StatusCode, attempts: r.Attempts}
+	})
+
+	for _, p := range []string{"/flaky", "/limited", "/missing"} {
+		c.AddRequest(&crawler.RequestMessage{URL: mustParse(t, ts.URL+p)})
+	}
+
+	c.Start()
+
+	expected := map[string]result{
+		"/flaky":   {statusCode: http.StatusOK, attempts: 3},
+		"/limited": {statusCode: http.StatusTooManyRequests, attempts: 3},
+		"/missing": {statusCode: http.StatusNotFound, attempts: 1},
+	}
+
+	for p, e := range expected {
+		if results[p] != e {
+			t.Errorf("%s: expected %+v got %+v", p, e, results[p])
+		}
+	}
+}
+
+// TestSlowdownWithoutRetries tests the host is slowed down when it responds with a 429 status
+// code even if the request is not retried.
+func TestSlowdownWithoutRetries(t *testing.T) {
+	lock := &sync.Mutex{}
+	times := make(map[string]time.Time)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		times[r.URL.Path] = time.Now()
+		lock.Unlock()
+
+		if r.URL.Path == "/limited" {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer ts.Close()
+
+	client := crawler.NewBasicClient(&crawler.ClientOptions{UserAgent: "TEST_UA"}, newJarClient())
+	c := crawler.NewCrawler(mustParse(t, ts.URL+"/limited"), &crawler.Options{
+		CrawlLimit:    10,
+		Workers:       1,
+		RetryAttempts: 1,
+	}, client)
+
+	for _, p := range []string{"/limited", "/next"} {
+		c.AddRequest(&crawler.RequestMessage{URL: mustParse(t, ts.URL+p)})
+	}
+
+	c.Start()
+
+	// The first slowdown adds a delay of half a second between the requests to the host.
+	if d := times["/next"].Sub(times["/limited"]); d < 400*time.Millisecond {
+		t.Errorf("expected the host delay to grow after the 429 response, the next request was made after %s", d)
+	}
+}
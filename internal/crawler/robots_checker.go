@@ -2,14 +2,34 @@ package crawler
 
 import (
 	"errors"
+	"io"
 	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/temoto/robotstxt"
 )
 
+// Max size of the robots.txt bodies kept by the RobotsChecker, larger files are truncated.
+const maxRobotsSize = 512 * 1024
+
+// RobotsFile is a robots.txt file requested by the RobotsChecker.
+type RobotsFile struct {
+	Host       string
+	URL        string
+	StatusCode int    // Status code of the response, zero if the request failed.
+	Body       []byte // Body of the file, only kept if the status code is 200.
+	Overridden bool   // The body is the project's override and the live file was not requested.
+}
+
 type RobotsChecker struct {
 	robotsMap map[string]*robotstxt.RobotsData
+	bodies    map[string][]byte
+	files     map[string]*RobotsFile
+	overrides map[string][]byte
 	rlock     *sync.RWMutex
 	client    Client
 }
@@ -17,6 +37,9 @@ type RobotsChecker struct {
 func NewRobotsChecker(client Client) *RobotsChecker {
 	return &RobotsChecker{
 		robotsMap: make(map[string]*robotstxt.RobotsData),
+		bodies:    make(map[string][]byte),
+		files:     make(map[string]*RobotsFile),
+		overrides: make(map[string][]byte),
 		rlock:     &sync.RWMutex{},
 		client:    client,
 	}
@@ -29,12 +52,17 @@ func (r *RobotsChecker) IsBlocked(u *url.URL) bool {
 		return false
 	}
 
+	return !robot.TestAgent(robotsPath(u), r.client.GetUA())
+}
+
+// robotsPath returns the URL's path and query as they are tested against the robots.txt rules.
+func robotsPath(u *url.URL) string {
 	path := u.EscapedPath()
 	if u.RawQuery != "" {
 		path += "?" + u.Query().Encode()
 	}
 
-	return !robot.TestAgent(path, r.client.GetUA())
+	return path
 }
 
 // Returns true if the robots.txt file exists and is valid
@@ -61,6 +89,37 @@ func (r *RobotsChecker) GetSitemaps(u *url.URL) []string {
 	return robot.Sitemaps
 }
 
+// Returns the delay between requests the robots.txt file asks for the client's user agent.
+// It is the largest of the Crawl-delay and the interval set by the Request-rate directives.
+// A zero duration is returned if the robots.txt file does not exist or has no delay.
+func (r *RobotsChecker) CrawlDelay(u *url.URL) time.Duration {
+	robot, err := r.getRobotsMap(u)
+	if err != nil || robot == nil {
+		return 0
+	}
+
+	r.rlock.RLock()
+	body := r.bodies[u.Host]
+	r.rlock.RUnlock()
+
+	delay := robot.FindGroup(r.client.GetUA()).CrawlDelay
+	if rate := requestRate(body, r.client.GetUA()); rate > delay {
+		delay = rate
+	}
+
+	return delay
+}
+
+// Override sets the robots.txt body used for the host instead of requesting the live file.
+// The overridden files are included in the files with the Overridden flag set.
+func (r *RobotsChecker) Override(host string, body []byte) {
+	r.rlock.Lock()
+	defer r.rlock.Unlock()
+
+	r.overrides[host] = body
+	delete(r.robotsMap, host)
+}
+
 // Returns a RobotsData checking if it has already been created and stored in the robotsMap
 func (r *RobotsChecker) getRobotsMap(u *url.URL) (*robotstxt.RobotsData, error) {
 	r.rlock.Lock()
@@ -71,25 +130,165 @@ func (r *RobotsChecker) getRobotsMap(u *url.URL) (*robotstxt.RobotsData, error)
 		return robot, nil
 	}
 
-	resp, err := r.client.Get(u.Scheme + "://" + u.Host + "/robots.txt")
+	if body, ok := r.overrides[u.Host]; ok {
+		r.files[u.Host] = &RobotsFile{
+			Host:       u.Host,
+			URL:        u.Scheme + "://" + u.Host + "/robots.txt",
+			StatusCode: 200,
+			Body:       body[:min(len(body), maxRobotsSize)],
+			Overridden: true,
+		}
+
+		robot, err := robotstxt.FromStatusAndBytes(200, body)
+		if err != nil {
+			r.robotsMap[u.Host] = nil
+			return nil, err
+		}
+
+		r.robotsMap[u.Host] = robot
+		r.bodies[u.Host] = body
+
+		return robot, nil
+	}
+
+	file := &RobotsFile{Host: u.Host, URL: u.Scheme + "://" + u.Host + "/robots.txt"}
+	r.files[u.Host] = file
+
+	resp, err := r.client.Get(file.URL)
 	if err != nil {
 		r.robotsMap[u.Host] = nil
 		return nil, err
 	}
 	defer resp.Response.Body.Close()
 
+	file.StatusCode = resp.Response.StatusCode
 	if resp.Response.StatusCode != 200 {
 		r.robotsMap[u.Host] = nil
 		return nil, errors.New("robots.txt file does not exist")
 	}
 
-	robot, err = robotstxt.FromResponse(resp.Response)
+	body, err := io.ReadAll(resp.Response.Body)
+	if err != nil {
+		r.robotsMap[u.Host] = nil
+		return nil, err
+	}
+
+	file.Body = body[:min(len(body), maxRobotsSize)]
+
+	robot, err = robotstxt.FromStatusAndBytes(resp.Response.StatusCode, body)
 	if err != nil {
 		r.robotsMap[u.Host] = nil
 		return nil, err
 	}
 
 	r.robotsMap[u.Host] = robot
+	r.bodies[u.Host] = body
 
 	return robot, nil
 }
+
+// Files returns the robots.txt files requested or overridden so far sorted by host.
+func (r *RobotsChecker) Files() []RobotsFile {
+	r.rlock.RLock()
+	defer r.rlock.RUnlock()
+
+	files := []RobotsFile{}
+	for _, f := range r.files {
+		files = append(files, *f)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Host < files[j].Host
+	})
+
+	return files
+}
+
+// requestRate returns the interval between requests set by the Request-rate directive
+// of the group that matches the user agent. The directive's value is in the form of
+// "documents/time", where time may have an s, m or h unit suffix, ie: "1/5s".
+// Groups are matched the same way robotstxt matches them, the longest user agent prefix
+// wins and the "*" group is used if no other group matches.
+func requestRate(body []byte, agent string) time.Duration {
+	agent = strings.ToLower(agent)
+	rates := make(map[string]time.Duration)
+
+	var agents []string
+	lastAgent := false
+	for _, line := range strings.Split(string(body), "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent", "useragent":
+			if !lastAgent {
+				agents = []string{}
+			}
+			agents = append(agents, strings.ToLower(value))
+			lastAgent = true
+			continue
+		case "request-rate":
+			if d, ok := parseRequestRate(value); ok {
+				for _, a := range agents {
+					rates[a] = d
+				}
+			}
+		}
+
+		lastAgent = false
+	}
+
+	rate, prefixLen := rates["*"], 0
+	for a, d := range rates {
+		if a != "*" && strings.HasPrefix(agent, a) && len(a) > prefixLen {
+			prefixLen = len(a)
+			rate = d
+		}
+	}
+
+	return rate
+}
+
+// parseRequestRate parses a Request-rate value such as "1/10s" and returns the
+// interval between requests. An optional time window after the rate is ignored.
+func parseRequestRate(v string) (time.Duration, bool) {
+	fields := strings.Fields(v)
+	if len(fields) == 0 {
+		return 0, false
+	}
+
+	docs, period, ok := strings.Cut(fields[0], "/")
+	if !ok {
+		return 0, false
+	}
+
+	unit := time.Second
+	switch {
+	case strings.HasSuffix(period, "h"):
+		unit = time.Hour
+	case strings.HasSuffix(period, "m"):
+		unit = time.Minute
+	}
+	period = strings.TrimRight(period, "smh")
+
+	n, err := strconv.Atoi(docs)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+
+	t, err := strconv.ParseFloat(period, 64)
+	if err != nil || t < 0 {
+		return 0, false
+	}
+
+	return time.Duration(t * float64(unit) / float64(n)), true
+}
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stjudewashere/seonaut/internal/crawler"
 )
@@ -26,6 +27,20 @@ func (t *MockClient) Get(u string) (*crawler.ClientResponse, error) {
 		`
 		r.Body = io.NopCloser(bytes.NewBufferString(body))
 		r.StatusCode = 200
+	} else if strings.HasPrefix(u, "https://delay.example.com/") {
+		body := `
+		User-Agent: *
+		Crawl-delay: 2
+
+		User-Agent: test
+		Crawl-delay: 3
+		Request-rate: 1/10s
+
+		User-Agent: other
+		Request-rate: 1/1m
+		`
+		r.Body = io.NopCloser(bytes.NewBufferString(body))
+		r.StatusCode = 200
 	} else {
 		r.Body = io.NopCloser(bytes.NewBufferString(""))
 		r.StatusCode = 404
@@ -105,3 +120,99 @@ func TestGetSitemap(t *testing.T) {
 		t.Errorf("error getting sitemap from robots.txt in %s", u.String())
 	}
 }
+
+// TestCrawlDelay tests the delay is taken from the group matching the user agent,
+// using the Request-rate interval if it is greater than the Crawl-delay.
+func TestCrawlDelay(t *testing.T) {
+	robotsChecker := crawler.NewRobotsChecker(&MockClient{})
+	u, err := url.Parse("https://delay.example.com/")
+	if err != nil {
+		t.Errorf("url parse error %v", err)
+	}
+
+	if d := robotsChecker.CrawlDelay(u); d != 10*time.Second {
+		t.Errorf("expected crawl delay of 10s in %s, got %v", u.String(), d)
+	}
+
+	u, err = url.Parse("https://example.com/")
+	if err != nil {
+		t.Errorf("url parse error %v", err)
+	}
+
+	if d := robotsChecker.CrawlDelay(u); d != 0 {
+		t.Errorf("expected no crawl delay in %s, got %v", u.String(), d)
+	}
+
+	u, err = url.Parse("https://norobots.com/")
+	if err != nil {
+		t.Errorf("url parse error %v", err)
+	}
+
+	if d := robotsChecker.CrawlDelay(u); d != 0 {
+		t.Errorf("expected no crawl delay in %s, got %v", u.String(), d)
+	}
+}
+
+// TestRobotsFiles tests the requested robots.txt files are kept sorted by host.
+func TestRobotsFiles(t *testing.T) {
+	robotsChecker := crawler.NewRobotsChecker(&MockClient{})
+	for _, s := range []string{"https://norobots.com/", "https://example.com/page", "https://example.com/"} {
+		u, err := url.Parse(s)
+		if err != nil {
+			t.Errorf("url parse error %v", err)
+		}
+
+		robotsChecker.IsBlocked(u)
+	}
+
+	files := robotsChecker.Files()
+	if len(files) != 2 {
+		t.Fatalf("expected 2 robots.txt files, got %d", len(files))
+	}
+
+	if files[0].Host != "example.com" || files[0].StatusCode != 200 || !strings.Contains(string(files[0].Body), "Disallow: /disallowed") {
+		t.Errorf("unexpected robots.txt file %+v", files[0])
+	}
+
+	if files[1].Host != "norobots.com" || files[1].StatusCode != 404 || len(files[1].Body) != 0 {
+		t.Errorf("unexpected robots.txt file %+v", files[1])
+	}
+}
+
+// TestRobotsOverride tests the overridden robots.txt is used instead of the live file.
+func TestRobotsOverride(t *testing.T) {
+	robotsChecker := crawler.NewRobotsChecker(&MockClient{})
+	robotsChecker.Override("example.com", []byte("User-agent: *\nDisallow: /new\nSitemap: /new-sitemap.xml"))
+
+	u, err := url.Parse("https://example.com/disallowed")
+	if err != nil {
+		t.Errorf("url parse error %v", err)
+	}
+
+	if robotsChecker.IsBlocked(u) {
+		t.Errorf("url %s should not be blocked", u.String())
+	}
+
+	u, err = url.Parse("https://example.com/new")
+	if err != nil {
+		t.Errorf("url parse error %v", err)
+	}
+
+	if !robotsChecker.IsBlocked(u) {
+		t.Errorf("url %s should be blocked", u.String())
+	}
+
+	sitemaps := robotsChecker.GetSitemaps(u)
+	if len(sitemaps) != 1 || sitemaps[0] != "/new-sitemap.xml" {
+		t.Errorf("expected the sitemap of the overridden robots.txt, got %v", sitemaps)
+	}
+
+	files := robotsChecker.Files()
+	if len(files) != 1 || !files[0].Overridden || files[0].StatusCode != 200 {
+		t.Fatalf("expected the overridden robots.txt file, got %+v", files)
+	}
+
+	if !strings.HasPrefix(string(files[0].Body), "User-agent: *\nDisallow: /new") {
+		t.Errorf("expected the body of the overridden robots.txt, got %q", files[0].Body)
+	}
+}
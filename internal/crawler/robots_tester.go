@@ -0,0 +1,159 @@
+package crawler
+
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/temoto/robotstxt"
+)
+
+// RobotsMatch is the result of testing an URL against the rules of a robots.txt file.
+type RobotsMatch struct {
+	Allowed bool
+	Group   string // User agent of the group whose rules apply, empty if no group applies.
+	Line    int    // Line number of the matching rule, zero if no rule matches.
+	Rule    string // Matching rule as it is written in the file.
+}
+
+// robotsRule is an allow or disallow rule of a robots.txt group.
+type robotsRule struct {
+	agents  []string
+	allow   bool
+	pattern string
+	line    int
+	text    string
+}
+
+// MatchRobotsRules tests the URL against the robots.txt rules for the user agent. Whether the
+// URL is allowed is decided by robotstxt, the same way the RobotsChecker decides it during the
+// crawl, and the URL is allowed if the file can't be parsed. The group and the rule that decide
+// it are found by parsing the file again, keeping the line numbers. The group is chosen the same
+// way as in requestRate and the rules of all the groups with that user agent are combined. The
+// rule is the first of the longest matching rules, as robotstxt picks it, and it is left empty if
+// it doesn't agree with the robotstxt result.
+func MatchRobotsRules(body []byte, agent string, u *url.URL) RobotsMatch {
+	m := RobotsMatch{Allowed: true}
+
+	path := robotsPath(u)
+	if robot, err := robotstxt.FromStatusAndBytes(200, body); err == nil {
+		m.Allowed = robot.TestAgent(path, agent)
+	}
+
+	agent = strings.ToLower(strings.TrimSpace(agent))
+
+	rules := []robotsRule{}
+	groups := make(map[string]bool)
+
+	var agents []string
+	lastAgent := false
+	for i, line := range strings.Split(strings.TrimPrefix(string(body), "\uFEFF"), "\n") {
+		text := strings.TrimSpace(line)
+		if c := strings.Index(line, "#"); c >= 0 {
+			line = line[:c]
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent", "useragent":
+			if !lastAgent {
+				agents = []string{}
+			}
+			agents = append(agents, strings.ToLower(value))
+			groups[strings.ToLower(value)] = true
+			lastAgent = true
+			continue
+		case "allow", "disallow":
+			if value != "" && len(agents) > 0 {
+				rules = append(rules, robotsRule{
+					agents:  agents,
+					allow:   key == "allow",
+					pattern: value,
+					line:    i + 1,
+					text:    text,
+				})
+			}
+		}
+
+		lastAgent = false
+	}
+
+	prefixLen := 0
+	if groups["*"] {
+		m.Group = "*"
+	}
+
+	for a := range groups {
+		if a != "*" && strings.HasPrefix(agent, a) && len(a) > prefixLen {
+			prefixLen = len(a)
+			m.Group = a
+		}
+	}
+
+	if m.Group == "" {
+		return m
+	}
+
+	var match *robotsRule
+	matchLen := 0
+	for i, r := range rules {
+		if !slices.Contains(r.agents, m.Group) {
+			continue
+		}
+
+		if l := robotsPatternMatch(r.pattern, path); l > matchLen {
+			matchLen = l
+			match = &rules[i]
+		}
+	}
+
+	if match != nil && match.allow == m.Allowed {
+		m.Line = match.line
+		m.Rule = match.text
+	}
+
+	return m
+}
+
+// robotsPatternMatch returns the length robotstxt gives to the rule's pattern if it matches the
+// path, or zero if it doesn't match. The patterns are normalized as robotstxt normalizes them.
+// The patterns with "*" or "$" wildcards are matched as regular expressions, measured by the
+// length of the expression, and the other patterns match the start of the path. The "/" pattern
+// is the weakest match.
+func robotsPatternMatch(pattern, path string) int {
+	if !strings.HasPrefix(pattern, "*") && !strings.HasPrefix(pattern, "/") {
+		pattern = "/" + pattern
+	}
+	pattern = strings.TrimRight(pattern, "*")
+
+	if strings.ContainsAny(pattern, "*$") {
+		expr := regexp.QuoteMeta(pattern)
+		expr = strings.ReplaceAll(expr, `\*`, ".*")
+		expr = strings.ReplaceAll(expr, `\$`, "$")
+
+		re, err := regexp.Compile(expr)
+		if err != nil || !re.MatchString(path) {
+			return 0
+		}
+
+		return len(re.String())
+	}
+
+	if pattern == "/" {
+		return 1
+	}
+
+	if strings.HasPrefix(path, pattern) {
+		return len(pattern)
+	}
+
+	return 0
+}
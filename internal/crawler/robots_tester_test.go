@@ -0,0 +1,120 @@
+package crawler_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+)
+
+const testRobotsTxt = `User-agent: *
+Disallow: /private
+Allow: /private/public
+Disallow: /*.pdf$
+
+User-agent: Googlebot
+User-agent: Bingbot
+Disallow: /search # internal search
+Allow: /search
+
+User-agent: Googlebot-Image
+Disallow: /
+
+User-agent: googlebot
+Disallow: /tmp
+`
+
+// TestMatchRobotsRules tests the group and the rule matching the URL in a robots.txt file.
+func TestMatchRobotsRules(t *testing.T) {
+	table := []struct {
+		agent   string
+		url     string
+		allowed bool
+		group   string
+		line    int
+	}{
+		{"SeonautBot", "https://example.com/", true, "*", 0},
+		{"SeonautBot", "https://example.com/private/page", false, "*", 2},
+		{"SeonautBot", "https://example.com/private/public/page", true, "*", 3},
+		{"SeonautBot", "https://example.com/files/doc.pdf", false, "*", 4},
+		{"SeonautBot", "https://example.com/files/doc.pdf?v=1", true, "*", 0},
+		{"Googlebot", "https://example.com/private", true, "googlebot", 0},
+		{"Googlebot", "https://example.com/search?q=test", false, "googlebot", 8},
+		{"Googlebot", "https://example.com/tmp/file", false, "googlebot", 15},
+		{"Googlebot-Image/1.0", "https://example.com/image.jpg", false, "googlebot-image", 12},
+		{"bingbot", "https://example.com/tmp/file", true, "bingbot", 0},
+	}
+
+	for _, v := range table {
+		u, err := url.Parse(v.url)
+		if err != nil {
+			t.Fatalf("url parse error %v", err)
+		}
+
+		m := crawler.MatchRobotsRules([]byte(testRobotsTxt), v.agent, u)
+		if m.Allowed != v.allowed || m.Group != v.group || m.Line != v.line {
+			t.Errorf("%s %s: expected allowed %v group %q line %d, got %+v", v.agent, v.url, v.allowed, v.group, v.line, m)
+		}
+	}
+}
+
+// TestMatchRobotsRulesNoGroup tests all URLs are allowed if no group applies to the user agent.
+func TestMatchRobotsRulesNoGroup(t *testing.T) {
+	u, err := url.Parse("https://example.com/private")
+	if err != nil {
+		t.Fatalf("url parse error %v", err)
+	}
+
+	m := crawler.MatchRobotsRules([]byte("User-agent: Googlebot\nDisallow: /"), "SeonautBot", u)
+	if !m.Allowed || m.Group != "" || m.Line != 0 {
+		t.Errorf("expected URL to be allowed without a group, got %+v", m)
+	}
+}
+
+const parityRobotsTxt = `User-agent: *
+Disallow: /
+Allow: /public
+Allow: docs
+Disallow: /docs/drafts*
+Allow: /*.css
+Disallow: /*?print=
+Allow: /shop/*/item$
+`
+
+// TestMatchRobotsRulesParity tests the URLs are allowed or blocked as the RobotsChecker does
+// during the crawl, and that the rule reported for them is the one deciding it.
+func TestMatchRobotsRulesParity(t *testing.T) {
+	robotsChecker := crawler.NewRobotsChecker(&MockClient{})
+	robotsChecker.Override("example.com", []byte(parityRobotsTxt))
+
+	table := []struct {
+		url  string
+		line int
+	}{
+		{"https://example.com/", 2},
+		{"https://example.com/private", 2},
+		{"https://example.com/public/page", 3},
+		{"https://example.com/docs/", 4},
+		{"https://example.com/docs/drafts/page", 5},
+		{"https://example.com/private/style.css", 6},
+		{"https://example.com/public/page?print=1", 7},
+		{"https://example.com/shop/shoes/item", 8},
+		{"https://example.com/shop/shoes/item/reviews", 2},
+	}
+
+	for _, v := range table {
+		u, err := url.Parse(v.url)
+		if err != nil {
+			t.Fatalf("url parse error %v", err)
+		}
+
+		m := crawler.MatchRobotsRules([]byte(parityRobotsTxt), "TEST UA", u)
+		if blocked := robotsChecker.IsBlocked(u); m.Allowed == blocked {
+			t.Errorf("%s: allowed %v but blocked by the robots checker %v", v.url, m.Allowed, blocked)
+		}
+
+		if m.Line != v.line {
+			t.Errorf("%s: expected line %d, got %+v", v.url, v.line, m)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package crawler
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	throttleWindow       = 20                     // Number of responses in the rolling window.
+	throttleSlowTTFB     = 800                    // Average TTFB in milliseconds that increases the throttle level.
+	throttleFastTTFB     = 400                    // Average TTFB in milliseconds that decreases the throttle level.
+	throttleMaxErrorRate = 0.2                    // Rate of failed responses that increases the throttle level.
+	throttleMaxLevel     = 5                      // Max throttle level.
+	throttleLevelDelay   = 500 * time.Millisecond // Delay added before each request for every throttle level.
+	throttleWaitInterval = time.Second            // Interval between checks of the paused workers.
+)
+
+// ThrottleState holds the adaptive throttle's state along with the response stats it is based on.
+type ThrottleState struct {
+	Level     int           // Throttle level, zero means the crawler is not throttled.
+	Workers   int           // Number of workers allowed to make requests.
+	Delay     time.Duration // Delay added before each request.
+	AvgTTFB   int           // Average TTFB in milliseconds of the responses in the window.
+	ErrorRate float64       // Rate of failed responses in the window.
+}
+
+type ThrottleCallback func(s ThrottleState)
+
+// Throttle keeps a rolling window of the response times and errors of the crawl. When the server's
+// latency or error rate rises the throttle level is increased, reducing the number of workers and
+// increasing the delay between requests. Once the server recovers the level is decreased again.
+// A nil Throttle never throttles the crawler.
+type Throttle struct {
+	maxWorkers  int
+	ttfb        [throttleWindow]int
+	failed      [throttleWindow]bool
+	next        int
+	count       int
+	sinceChange int
+	level       int
+	lock        *sync.Mutex
+}
+
+type throttleStats struct {
+	avgTTFB   int
+	errorRate float64
+}
+
+// NewThrottle returns a new Throttle for a crawler with the specified number of workers.
+func NewThrottle(workers int) *Throttle {
+	return &Throttle{
+		maxWorkers: workers,
+		lock:       &sync.Mutex{},
+	}
+}
+
+// Observe adds a response's TTFB and whether it failed to the rolling window and updates the
+// throttle level. The level is only changed once the window is full and enough responses have
+// been observed since the last change. It returns true if the level changed.
+func (t *Throttle) Observe(ttfb int, failed bool) bool {
+	if t == nil {
+		return false
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.ttfb[t.next] = ttfb
+	t.failed[t.next] = failed
+	t.next = (t.next + 1) % throttleWindow
+	t.count = min(t.count+1, throttleWindow)
+	t.sinceChange++
+
+	if t.count < throttleWindow || t.sinceChange < throttleWindow/2 {
+		return false
+	}
+
+	s := t.stats()
+	level := t.level
+	switch {
+	case s.avgTTFB > throttleSlowTTFB || s.errorRate > throttleMaxErrorRate:
+		level = min(level+1, throttleMaxLevel)
+	case s.avgTTFB < throttleFastTTFB && s.errorRate < throttleMaxErrorRate/2:
+		level = max(level-1, 0)
+	}
+
+	if level == t.level {
+		return false
+	}
+
+	t.level = level
+	t.sinceChange = 0
+
+	return true
+}
+
+// State returns the throttle's current state.
+func (t *Throttle) State() ThrottleState {
+	if t == nil {
+		return ThrottleState{}
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	s := t.stats()
+
+	return ThrottleState{
+		Level:     t.level,
+		Workers:   t.workers(),
+		Delay:     time.Duration(t.level) * throttleLevelDelay,
+		AvgTTFB:   s.avgTTFB,
+		ErrorRate: s.errorRate,
+	}
+}
+
+// Workers returns the number of workers allowed to make requests. The number of workers is
+// halved on each throttle level, but there is always at least one worker.
+func (t *Throttle) Workers() int {
+	if t == nil {
+		return math.MaxInt
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.workers()
+}
+
+// Delay returns the delay that must be added before each request.
+func (t *Throttle) Delay() time.Duration {
+	if t == nil {
+		return 0
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return time.Duration(t.level) * throttleLevelDelay
+}
+
+// workers returns the number of allowed workers, the lock must be held by the caller.
+func (t *Throttle) workers() int {
+	return max(t.maxWorkers>>t.level, 1)
+}
+
+// stats returns the average TTFB and error rate of the responses in the window,
+// the lock must be held by the caller.
+func (t *Throttle) stats() throttleStats {
+	if t.count == 0 {
+		return throttleStats{}
+	}
+
+	total, failed := 0, 0
+	for i := 0; i < t.count; i++ {
+		total += t.ttfb[i]
+		if t.failed[i] {
+			failed++
+		}
+	}
+
+	return throttleStats{
+		avgTTFB:   total / t.count,
+		errorRate: float64(failed) / float64(t.count),
+	}
+}
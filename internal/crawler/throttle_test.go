@@ -0,0 +1,52 @@
+package crawler_test
+
+import (
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+)
+
+// TestThrottle tests the throttle level rises with slow responses and drops once they recover.
+func TestThrottle(t *testing.T) {
+	throttle := crawler.NewThrottle(4)
+
+	observe := func(n, ttfb int, failed bool) {
+		for i := 0; i < n; i++ {
+			throttle.Observe(ttfb, failed)
+		}
+	}
+
+	observe(20, 100, false)
+	if s := throttle.State(); s.Level != 0 || s.Workers != 4 || s.Delay != 0 {
+		t.Errorf("expected no throttling with fast responses, got %+v", s)
+	}
+
+	observe(20, 2000, false)
+	s := throttle.State()
+	if s.Level == 0 || s.Workers >= 4 || s.Delay == 0 {
+		t.Errorf("expected throttling with slow responses, got %+v", s)
+	}
+
+	observe(200, 100, false)
+	if s := throttle.State(); s.Level != 0 || s.Workers != 4 {
+		t.Errorf("expected the throttle to recover with fast responses, got %+v", s)
+	}
+
+	observe(20, 100, true)
+	if s := throttle.State(); s.Level == 0 || s.ErrorRate == 0 {
+		t.Errorf("expected throttling with failed responses, got %+v", s)
+	}
+}
+
+// TestThrottleNil tests a nil throttle doesn't throttle the crawler.
+func TestThrottleNil(t *testing.T) {
+	var throttle *crawler.Throttle
+
+	if throttle.Observe(5000, true) {
+		t.Error("expected a nil throttle to not change its level")
+	}
+
+	if throttle.Delay() != 0 || throttle.Workers() < 1 {
+		t.Errorf("expected a nil throttle to not throttle, got delay %v and %d workers", throttle.Delay(), throttle.Workers())
+	}
+}
@@ -0,0 +1,181 @@
+package crawler
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/url"
+	"sort"
+	"sync"
+)
+
+// TLSInfo contains the details of the TLS connection to a host, including the certificate
+// chain sent by the server. If the handshake failed because the certificate couldn't be
+// verified the certificates are still available along with the handshake error.
+type TLSInfo struct {
+	Host         string
+	URL          string // URL of the request that opened the connection.
+	Protocol     string // Negotiated protocol, "h2" or "http/1.1".
+	Version      uint16
+	CipherSuite  uint16
+	Certificates []*x509.Certificate // Certificate chain starting with the leaf certificate.
+	Error        error               // Handshake error, nil if the handshake succeeded.
+}
+
+// handshakeError is a certificate verification error returned by the VerifyConnection callback
+// of the configs created with TLSConfig. It keeps the handshake's connection state, which is not
+// available once the handshake fails, so the connection details can still be recorded.
+type handshakeError struct {
+	state tls.ConnectionState
+	err   error
+}
+
+func (e *handshakeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *handshakeError) Unwrap() error {
+	return e.err
+}
+
+// TLSConfig returns a copy of the config that verifies the server's certificates in the
+// VerifyConnection callback instead of during the handshake. The verification is the same,
+// so the requests to hosts with invalid certificates still fail, but the handshake's details
+// are recorded along with the verification error. The hosts requested by IP address don't
+// send the server name, so only their certificate chain is verified and the hostname mismatch
+// is reported instead.
+func TLSConfig(config *tls.Config) *tls.Config {
+	c := &tls.Config{}
+	if config != nil {
+		c = config.Clone()
+	}
+
+	if c.InsecureSkipVerify {
+		return c
+	}
+
+	roots := c.RootCAs
+	c.InsecureSkipVerify = true
+	c.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return &handshakeError{state: cs, err: errors.New("tls: server sent no certificates")}
+		}
+
+		opts := x509.VerifyOptions{
+			Roots:         roots,
+			DNSName:       cs.ServerName,
+			Intermediates: x509.NewCertPool(),
+		}
+
+		for _, cert := range cs.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+
+		if _, err := cs.PeerCertificates[0].Verify(opts); err != nil {
+			return &handshakeError{
+				state: cs,
+				err:   &tls.CertificateVerificationError{UnverifiedCertificates: cs.PeerCertificates, Err: err},
+			}
+		}
+
+		return nil
+	}
+
+	return c
+}
+
+// tlsRecorder keeps the first TLS connection details of each host.
+type tlsRecorder struct {
+	lock  sync.Mutex
+	hosts map[string]*TLSInfo
+}
+
+// newTLSInfo returns the TLS details of a handshake. The connection state is empty
+// if the handshake failed, in that case the state is taken from the error if it was
+// kept, otherwise only the certificates are taken from the error.
+func newTLSInfo(u *url.URL, cs tls.ConnectionState, err error) *TLSInfo {
+	var hsErr *handshakeError
+	if errors.As(err, &hsErr) {
+		cs = hsErr.state
+	}
+
+	info := &TLSInfo{
+		Host:         u.Host,
+		URL:          u.String(),
+		Protocol:     cs.NegotiatedProtocol,
+		Version:      cs.Version,
+		CipherSuite:  cs.CipherSuite,
+		Certificates: cs.PeerCertificates,
+		Error:        err,
+	}
+
+	if info.Protocol == "" && info.Version != 0 {
+		info.Protocol = "http/1.1"
+	}
+
+	var verificationErr *tls.CertificateVerificationError
+	if errors.As(err, &verificationErr) {
+		info.Certificates = verificationErr.UnverifiedCertificates
+	}
+
+	return info
+}
+
+// record stores the TLS details of the host unless its certificates were already recorded.
+func (r *tlsRecorder) record(info *TLSInfo) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.hosts == nil {
+		r.hosts = make(map[string]*TLSInfo)
+	}
+
+	if h, ok := r.hosts[info.Host]; ok && len(h.Certificates) > 0 {
+		return
+	}
+
+	r.hosts[info.Host] = info
+}
+
+// list returns the TLS connection details of the hosts sorted by host name.
+func (r *tlsRecorder) list() []TLSInfo {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	hosts := []TLSInfo{}
+	for _, h := range r.hosts {
+		hosts = append(hosts, *h)
+	}
+
+	sort.Slice(hosts, func(i, j int) bool {
+		return hosts[i].Host < hosts[j].Host
+	})
+
+	return hosts
+}
+
+// Leaf returns the server's certificate or nil if there are no certificates.
+func (i *TLSInfo) Leaf() *x509.Certificate {
+	if len(i.Certificates) == 0 {
+		return nil
+	}
+
+	return i.Certificates[0]
+}
+
+// HostnameMismatch returns true if the server's certificate is not valid for the host.
+func (i *TLSInfo) HostnameMismatch() bool {
+	leaf := i.Leaf()
+	if leaf == nil {
+		return false
+	}
+
+	u := url.URL{Host: i.Host}
+
+	return leaf.VerifyHostname(u.Hostname()) != nil
+}
+
+// ObsoleteVersion returns true if the connection uses a TLS version older than TLS 1.2.
+func (i *TLSInfo) ObsoleteVersion() bool {
+	return i.Version != 0 && i.Version < tls.VersionTLS12
+}
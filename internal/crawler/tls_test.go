@@ -0,0 +1,118 @@
+package crawler_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+)
+
+func TestTLSHosts(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	client := crawler.NewBasicClient(&crawler.ClientOptions{UserAgent: "test"}, ts.Client())
+	if _, err := client.Get(ts.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	hosts := client.TLSHosts()
+	if len(hosts) != 1 {
+		t.Fatalf("TLSHosts: %d != 1", len(hosts))
+	}
+
+	h := hosts[0]
+	u, _ := url.Parse(ts.URL)
+	if h.Host != u.Host {
+		t.Errorf("Host %s != %s", h.Host, u.Host)
+	}
+
+	if h.Error != nil {
+		t.Errorf("Error %v != nil", h.Error)
+	}
+
+	if h.Protocol != "http/1.1" {
+		t.Errorf("Protocol %s != http/1.1", h.Protocol)
+	}
+
+	if h.Version != tls.VersionTLS13 {
+		t.Errorf("Version %s != TLS 1.3", tls.VersionName(h.Version))
+	}
+
+	if h.Leaf() == nil || h.Leaf().Issuer.Organization[0] != "Acme Co" {
+		t.Errorf("Leaf certificate %v", h.Leaf())
+	}
+
+	// The test certificate is valid for 127.0.0.1 and example.com.
+	if h.HostnameMismatch() {
+		t.Error("HostnameMismatch true != false")
+	}
+
+	if h.ObsoleteVersion() {
+		t.Error("ObsoleteVersion true != false")
+	}
+}
+
+func TestTLSHostsHTTP2(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	client := crawler.NewBasicClient(&crawler.ClientOptions{UserAgent: "test"}, ts.Client())
+	if _, err := client.Get(ts.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	hosts := client.TLSHosts()
+	if len(hosts) != 1 || hosts[0].Protocol != "h2" {
+		t.Errorf("TLSHosts: %v", hosts)
+	}
+}
+
+func TestTLSHostsVerificationError(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	// The default client doesn't trust the test server's certificate.
+	client := crawler.NewBasicClient(&crawler.ClientOptions{UserAgent: "test"}, &http.Client{})
+	if _, err := client.Get(ts.URL); err == nil {
+		t.Fatal("Get: expected certificate error")
+	}
+
+	hosts := client.TLSHosts()
+	if len(hosts) != 1 {
+		t.Fatalf("TLSHosts: %d != 1", len(hosts))
+	}
+
+	if hosts[0].Error == nil {
+		t.Error("Error nil != certificate error")
+	}
+
+	if hosts[0].Leaf() == nil {
+		t.Error("Leaf nil, expected the unverified certificate")
+	}
+}
+
+func TestTLSHostnameMismatch(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	h := crawler.TLSInfo{
+		Host:         "wrong.example.org:443",
+		Version:      tls.VersionTLS11,
+		Certificates: []*x509.Certificate{ts.Certificate()},
+	}
+
+	if !h.HostnameMismatch() {
+		t.Error("HostnameMismatch false != true")
+	}
+
+	if !h.ObsoleteVersion() {
+		t.Error("ObsoleteVersion false != true")
+	}
+}
@@ -0,0 +1,105 @@
+package crawler
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// URLRules is an ordered list of include and exclude rules matched against the URL's
+// path and query. The first matching rule decides if the URL is allowed. If no rule
+// matches, the URL is allowed only if there are no include rules.
+type URLRules struct {
+	rules      []urlRule
+	hasInclude bool
+}
+
+type urlRule struct {
+	include bool
+	re      *regexp.Regexp
+}
+
+// ParseURLRules parses the rules in s, one per line. Each line starts with "include" or
+// "exclude" followed by a glob pattern, where "*" matches any sequence of characters, or a
+// regular expression prefixed with "regex:". Empty lines and lines starting with "#" are
+// ignored. For example:
+//
+//	include /blog/*
+//	exclude regex:^/(search|cart)
+func ParseURLRules(s string) (*URLRules, error) {
+	r := &URLRules{}
+
+	for n, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		action, pattern, _ := strings.Cut(line, " ")
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			return nil, fmt.Errorf("line %d: missing pattern", n+1)
+		}
+
+		rule := urlRule{}
+		switch strings.ToLower(action) {
+		case "include":
+			rule.include = true
+			r.hasInclude = true
+		case "exclude":
+			rule.include = false
+		default:
+			return nil, fmt.Errorf("line %d: unknown action %q", n+1, action)
+		}
+
+		var err error
+		if expr, ok := strings.CutPrefix(pattern, "regex:"); ok {
+			rule.re, err = regexp.Compile(expr)
+		} else {
+			rule.re, err = globToRegexp(pattern)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n+1, err)
+		}
+
+		r.rules = append(r.rules, rule)
+	}
+
+	return r, nil
+}
+
+// Allowed returns true if the URL is allowed by the rules.
+func (r *URLRules) Allowed(u *url.URL) bool {
+	if r == nil || len(r.rules) == 0 {
+		return true
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+
+	for _, rule := range r.rules {
+		if rule.re.MatchString(path) {
+			return rule.include
+		}
+	}
+
+	return !r.hasInclude
+}
+
+// globToRegexp converts a glob pattern into an anchored regular expression.
+func globToRegexp(g string) (*regexp.Regexp, error) {
+	parts := strings.Split(g, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+
+	return regexp.Compile("^" + strings.Join(parts, ".*") + "$")
+}
@@ -0,0 +1,73 @@
+package crawler_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+)
+
+func TestURLRules(t *testing.T) {
+	rules, err := crawler.ParseURLRules(`
+		# Skip the blog's search
+		exclude /blog/search*
+		include /blog/*
+		include regex:^/products/[0-9]+$
+	`)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	table := map[string]bool{
+		"https://example.com/blog/post":            true,
+		"https://example.com/blog/post?page=2":     true,
+		"https://example.com/blog/search?q=x":      false,
+		"https://example.com/products/123":         true,
+		"https://example.com/products/123/reviews": false,
+		"https://example.com/about":                false,
+	}
+
+	for u, expected := range table {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			t.Fatalf("url parse error %v", err)
+		}
+
+		if rules.Allowed(parsed) != expected {
+			t.Errorf("%s allowed should be %v", u, expected)
+		}
+	}
+}
+
+func TestURLRulesExcludeOnly(t *testing.T) {
+	rules, err := crawler.ParseURLRules("exclude /cart*\nexclude regex:[?&]sort=")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	table := map[string]bool{
+		"https://example.com/":              true,
+		"https://example.com/cart/checkout": false,
+		"https://example.com/shop?sort=asc": false,
+		"https://example.com/shop?page=2":   true,
+	}
+
+	for u, expected := range table {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			t.Fatalf("url parse error %v", err)
+		}
+
+		if rules.Allowed(parsed) != expected {
+			t.Errorf("%s allowed should be %v", u, expected)
+		}
+	}
+}
+
+func TestURLRulesErrors(t *testing.T) {
+	for _, r := range []string{"allow /blog", "include", "exclude regex:(["} {
+		if _, err := crawler.ParseURLRules(r); err == nil {
+			t.Errorf("rules %q should return an error", r)
+		}
+	}
+}
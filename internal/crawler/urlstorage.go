@@ -4,6 +4,14 @@ import (
 	"sync"
 )
 
+// URLStore keeps the URLs seen by the crawler.
+type URLStore interface {
+	Seen(u string) bool
+	Add(u string)
+	Iterate(f func(string))
+}
+
+// URLStorage is an URLStore that keeps the URLs in memory.
 type URLStorage struct {
 	seen map[string]bool
 	lock sync.RWMutex
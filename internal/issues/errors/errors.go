@@ -81,4 +81,12 @@ const (
 	ErrorMissingImgElement                       // Pages with Picture missing the img element
 	ErrorMetasInBody                             // Pages with meta tags in the document's body
 	ErrorNosnippet                               // Pages with the nosnippet directive
+	ErrorRedirectExternal                        // Pages with redirect chains leaving the crawled domains
+	ErrorRedirectTooLong                         // Pages with redirect chains exceeding the max number of hops
+	ErrorUncompressed                            // Text resources served without compression
+	ErrorCertificateExpiring                     // Hosts with TLS certificates expired or about to expire
+	ErrorCertificateHostname                     // Hosts with TLS certificates not valid for the host name
+	ErrorObsoleteTLS                             // Hosts using TLS versions older than TLS 1.2
+	ErrorNearDuplicateContent                    // Pages with text very similar to other pages
+	ErrorUnsupportedEncoding                     // Responses with a content encoding the crawler can't decode
 )
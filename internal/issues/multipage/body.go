@@ -1,8 +1,11 @@
 package multipage
 
 import (
+	"log"
+
 	"github.com/stjudewashere/seonaut/internal/issues/errors"
 	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/simhash"
 )
 
 // Creates a MultipageIssueReporter object that contains the SQL query to check for pages with identical titles.
@@ -24,3 +27,50 @@ func (sr *SqlReporter) DuplicatedContent(c *models.Crawl) *models.MultipageIssue
 		ErrorType: errors.ErrorDuplicatedContent,
 	}
 }
+
+// Creates a MultipageIssueReporter object that streams the pages with text very similar to other pages.
+// The pages' SimHash fingerprints are clustered using the reporter's similarity threshold. Pages with
+// identical content are already reported as duplicated content, so only one of them is clustered.
+func (sr *SqlReporter) NearDuplicateContent(c *models.Crawl) *models.MultipageIssueReporter {
+	query := `
+		SELECT MIN(id), simhash
+		FROM pagereports
+		WHERE crawl_id = ? AND media_type = "text/html" AND body_hash <> "" AND simhash <> 0
+			AND status_code >= 200 AND status_code < 300
+		GROUP BY body_hash, simhash`
+
+	prStream := make(chan int64)
+
+	go func() {
+		defer close(prStream)
+
+		rows, err := sr.db.Query(query, c.Id)
+		if err != nil {
+			log.Printf("Error executing query: %s, Args: %v, Error: %v", query, c.Id, err)
+			return
+		}
+		defer rows.Close()
+
+		items := []simhash.Item{}
+		for rows.Next() {
+			var id, hash int64
+			if err := rows.Scan(&id, &hash); err != nil {
+				log.Printf("Error scanning results for query: %s, Args: %v, Error: %v", query, c.Id, err)
+				continue
+			}
+
+			items = append(items, simhash.Item{Id: id, Hash: uint64(hash)})
+		}
+
+		for _, cluster := range simhash.Clusters(items, sr.similarity) {
+			for _, id := range cluster.Ids {
+				prStream <- id
+			}
+		}
+	}()
+
+	return &models.MultipageIssueReporter{
+		Pstream:   prStream,
+		ErrorType: errors.ErrorNearDuplicateContent,
+	}
+}
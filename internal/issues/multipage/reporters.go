@@ -8,47 +8,86 @@ import (
 )
 
 type SqlReporter struct {
-	db *sql.DB
+	db         *sql.DB
+	similarity float64 // Min similarity, from 0 to 1, of the near duplicate pages.
 }
 
-// NewSqlReporter creates a new SqlReporter with the given SQL database connection.
-func NewSqlReporter(db *sql.DB) *SqlReporter {
+// NewSqlReporter creates a new SqlReporter with the given SQL database connection and
+// the min similarity of the pages reported as near duplicates.
+func NewSqlReporter(db *sql.DB, similarity float64) *SqlReporter {
 	return &SqlReporter{
-		db: db,
+		db:         db,
+		similarity: similarity,
 	}
 }
 
-// GetAllReporters returns a slice of all the implemented reporters in the SqlReporter.
-func (sr *SqlReporter) GetAllReporters() []models.MultipageCallback {
-	return []models.MultipageCallback{
+// reporter is a multipage reporter callback and whether it can run on list mode crawls.
+type reporter struct {
+	callback models.MultipageCallback
+	listMode bool
+}
+
+// reporters returns all the implemented reporters in the SqlReporter. The orphan pages and missing
+// hreflang return links reporters can't run on list mode crawls, as they would report false positives
+// for the pages that link to the listed URLs but were not crawled.
+func (sr *SqlReporter) reporters() []reporter {
+	return []reporter{
 		// Add content issue reporters
-		sr.DuplicatedContent,
+		{sr.DuplicatedContent, true},
+		{sr.NearDuplicateContent, true},
 
 		// Add status code issue reporters
-		sr.RedirectChainsReporter,
-		sr.RedirectLoopsReporter,
+		{sr.RedirectChainsReporter, true},
+		{sr.RedirectLoopsReporter, true},
 
 		// Add title issue reporters
-		sr.DuplicatedTitleReporter,
+		{sr.DuplicatedTitleReporter, true},
 
 		// Add description issue reporters
-		sr.DuplicatedDescriptionReporter,
+		{sr.DuplicatedDescriptionReporter, true},
 
 		// Add link issue reporters
-		sr.OrphanPagesReporter,
-		sr.NoFollowIndexableReporter,
-		sr.FollowNoFollowReporter,
+		{sr.OrphanPagesReporter, false},
+		{sr.NoFollowIndexableReporter, true},
+		{sr.FollowNoFollowReporter, true},
 
 		// Add hreflang reporters
-		sr.MissingHrelangReturnLinks,
-		sr.HreflangsToNonCanonical,
-		sr.HreflangNoindexable,
-		sr.MultipleLangReference,
+		{sr.MissingHrelangReturnLinks, false},
+		{sr.HreflangsToNonCanonical, true},
+		{sr.HreflangNoindexable, true},
+		{sr.MultipleLangReference, true},
 
 		// Add canonical issue reporters
-		sr.CanonicalizedToNonCanonical,
-		sr.CanonicalizedToNonIndexable,
+		{sr.CanonicalizedToNonCanonical, true},
+		{sr.CanonicalizedToNonIndexable, true},
+
+		// Add TLS issue reporters
+		{sr.CertificateExpiringReporter, true},
+		{sr.CertificateHostnameReporter, true},
+		{sr.ObsoleteTLSReporter, true},
+	}
+}
+
+// GetAllReporters returns a slice of all the implemented reporters in the SqlReporter.
+func (sr *SqlReporter) GetAllReporters() []models.MultipageCallback {
+	callbacks := []models.MultipageCallback{}
+	for _, r := range sr.reporters() {
+		callbacks = append(callbacks, r.callback)
+	}
+
+	return callbacks
+}
+
+// GetListModeReporters returns the reporters in GetAllReporters that can run on list mode crawls.
+func (sr *SqlReporter) GetListModeReporters() []models.MultipageCallback {
+	callbacks := []models.MultipageCallback{}
+	for _, r := range sr.reporters() {
+		if r.listMode {
+			callbacks = append(callbacks, r.callback)
+		}
 	}
+
+	return callbacks
 }
 
 // pageReportsQuery executes a SQL query and returns a channel of int64 which is used to send
@@ -0,0 +1,55 @@
+package multipage
+
+import (
+	"github.com/stjudewashere/seonaut/internal/issues/errors"
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for hosts with
+// TLS certificates that are expired or expire soon. The TLS issues are reported in all the
+// https pages of the host.
+func (sr *SqlReporter) CertificateExpiringReporter(c *models.Crawl) *models.MultipageIssueReporter {
+	query := `
+		SELECT
+			pagereports.id
+		FROM tls_hosts
+		INNER JOIN pagereports ON pagereports.crawl_id = tls_hosts.crawl_id AND pagereports.host = tls_hosts.host AND pagereports.scheme = 'https'
+		WHERE tls_hosts.crawl_id = ? AND tls_hosts.not_after < ?`
+
+	return &models.MultipageIssueReporter{
+		Pstream:   sr.pageReportsQuery(query, c.Id, c.Start.AddDate(0, 0, models.CertificateExpiryDays)),
+		ErrorType: errors.ErrorCertificateExpiring,
+	}
+}
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for hosts with
+// TLS certificates that are not valid for the host name.
+func (sr *SqlReporter) CertificateHostnameReporter(c *models.Crawl) *models.MultipageIssueReporter {
+	query := `
+		SELECT
+			pagereports.id
+		FROM tls_hosts
+		INNER JOIN pagereports ON pagereports.crawl_id = tls_hosts.crawl_id AND pagereports.host = tls_hosts.host AND pagereports.scheme = 'https'
+		WHERE tls_hosts.crawl_id = ? AND tls_hosts.hostname_mismatch = 1`
+
+	return &models.MultipageIssueReporter{
+		Pstream:   sr.pageReportsQuery(query, c.Id),
+		ErrorType: errors.ErrorCertificateHostname,
+	}
+}
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for hosts
+// using TLS versions older than TLS 1.2.
+func (sr *SqlReporter) ObsoleteTLSReporter(c *models.Crawl) *models.MultipageIssueReporter {
+	query := `
+		SELECT
+			pagereports.id
+		FROM tls_hosts
+		INNER JOIN pagereports ON pagereports.crawl_id = tls_hosts.crawl_id AND pagereports.host = tls_hosts.host AND pagereports.scheme = 'https'
+		WHERE tls_hosts.crawl_id = ? AND tls_hosts.obsolete_version = 1`
+
+	return &models.MultipageIssueReporter{
+		Pstream:   sr.pageReportsQuery(query, c.Id),
+		ErrorType: errors.ErrorObsoleteTLS,
+	}
+}
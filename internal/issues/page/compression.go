@@ -0,0 +1,61 @@
+package page
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/stjudewashere/seonaut/internal/issues/errors"
+	"github.com/stjudewashere/seonaut/internal/models"
+
+	"golang.org/x/net/html"
+)
+
+// Text responses smaller than this size in bytes are not worth compressing.
+const minCompressibleSize = 1400
+
+// Returns a report_manager.PageIssueReporter with a callback function that checks
+// if a text resource such as HTML, CSS or JavaScript is served without compression.
+// The callback returns true if the response is a successful text response larger than
+// the minimum compressible size without a Content-Encoding.
+func NewUncompressedReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if pageReport.StatusCode < 200 || pageReport.StatusCode >= 300 {
+			return false
+		}
+
+		if pageReport.Size < minCompressibleSize || !isText(pageReport.MediaType) {
+			return false
+		}
+
+		return pageReport.ContentEncoding == "" || pageReport.ContentEncoding == "identity"
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorUncompressed,
+		Callback:  c,
+	}
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that checks
+// if the response has a content encoding the crawler can't decode, so its body
+// could not be parsed.
+func NewUnsupportedEncodingReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		return pageReport.UndecodedBody
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorUnsupportedEncoding,
+		Callback:  c,
+	}
+}
+
+// isText returns true if the media type is a compressible text format.
+func isText(mediaType string) bool {
+	switch mediaType {
+	case "application/javascript", "application/json", "application/xml", "image/svg+xml":
+		return true
+	}
+
+	return strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "+xml") || strings.HasSuffix(mediaType, "+json")
+}
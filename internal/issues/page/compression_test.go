@@ -0,0 +1,92 @@
+package page_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/issues/errors"
+	"github.com/stjudewashere/seonaut/internal/issues/page"
+	"github.com/stjudewashere/seonaut/internal/models"
+
+	"golang.org/x/net/html"
+)
+
+// Test the Uncompressed reporter with a compressed text resource.
+// The reporter should not report the issue.
+func TestCompressedTextResource(t *testing.T) {
+	pageReport := &models.PageReport{
+		StatusCode:      200,
+		MediaType:       "text/css",
+		Size:            20000,
+		TransferSize:    4000,
+		ContentEncoding: "gzip",
+	}
+
+	reporter := page.NewUncompressedReporter()
+	if reporter.ErrorType != errors.ErrorUncompressed {
+		t.Errorf("TestCompressedTextResource: error type is not correct")
+	}
+
+	reportsIssue := reporter.Callback(pageReport, &html.Node{}, &http.Header{})
+
+	if reportsIssue == true {
+		t.Errorf("TestCompressedTextResource: reportsIssue should be false")
+	}
+}
+
+// Test the Uncompressed reporter with small and non text resources.
+// The reporter should not report the issue.
+func TestUncompressedExcludedResources(t *testing.T) {
+	pageReports := []*models.PageReport{
+		{StatusCode: 200, MediaType: "text/html", Size: 500, TransferSize: 500},
+		{StatusCode: 200, MediaType: "image/jpeg", Size: 20000, TransferSize: 20000},
+		{StatusCode: 404, MediaType: "text/html", Size: 20000, TransferSize: 20000},
+	}
+
+	reporter := page.NewUncompressedReporter()
+	for _, pageReport := range pageReports {
+		if reporter.Callback(pageReport, &html.Node{}, &http.Header{}) {
+			t.Errorf("TestUncompressedExcludedResources: %s %d reportsIssue should be false", pageReport.MediaType, pageReport.StatusCode)
+		}
+	}
+}
+
+// Test the Uncompressed reporter with uncompressed text resources.
+// The reporter should report the issue.
+func TestUncompressedTextResource(t *testing.T) {
+	pageReports := []*models.PageReport{
+		{StatusCode: 200, MediaType: "text/html", Size: 20000, TransferSize: 20000},
+		{StatusCode: 200, MediaType: "application/javascript", Size: 20000, TransferSize: 20000},
+		{StatusCode: 200, MediaType: "image/svg+xml", Size: 20000, TransferSize: 20000, ContentEncoding: "identity"},
+	}
+
+	reporter := page.NewUncompressedReporter()
+	for _, pageReport := range pageReports {
+		if !reporter.Callback(pageReport, &html.Node{}, &http.Header{}) {
+			t.Errorf("TestUncompressedTextResource: %s reportsIssue should be true", pageReport.MediaType)
+		}
+	}
+}
+
+// Test the UnsupportedEncoding reporter with decoded and undecoded bodies.
+// The reporter should only report the issue for the undecoded bodies.
+func TestUnsupportedEncoding(t *testing.T) {
+	table := []struct {
+		pageReport *models.PageReport
+		want       bool
+	}{
+		{&models.PageReport{StatusCode: 200, ContentEncoding: "gzip"}, false},
+		{&models.PageReport{StatusCode: 200, ContentEncoding: "zstd", UndecodedBody: true}, true},
+	}
+
+	reporter := page.NewUnsupportedEncodingReporter()
+	if reporter.ErrorType != errors.ErrorUnsupportedEncoding {
+		t.Errorf("TestUnsupportedEncoding: error type is not correct")
+	}
+
+	for _, tt := range table {
+		if reportsIssue := reporter.Callback(tt.pageReport, &html.Node{}, &http.Header{}); reportsIssue != tt.want {
+			t.Errorf("TestUnsupportedEncoding %s: reportsIssue should be %v", tt.pageReport.ContentEncoding, tt.want)
+		}
+	}
+}
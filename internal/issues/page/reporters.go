@@ -9,6 +9,8 @@ func GetAllReporters() []*models.PageIssueReporter {
 		NewStatus30xReporter(),
 		NewStatus40xReporter(),
 		NewStatus50xReporter(),
+		NewRedirectExternalReporter(),
+		NewRedirectTooLongReporter(),
 
 		// Add title issue reporters
 		NewEmptyTitleReporter(),
@@ -85,6 +87,10 @@ func GetAllReporters() []*models.PageIssueReporter {
 		// Add Time To Firts Byte reporter
 		NewSlowTTFBReporter(),
 
+		// Add compression reporters
+		NewUncompressedReporter(),
+		NewUnsupportedEncodingReporter(),
+
 		// Add form reporters
 		NewFormOnHTTPReporter(),
 		NewInsecureFormReporter(),
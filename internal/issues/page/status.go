@@ -59,3 +59,52 @@ func NewStatus50xReporter() *models.PageIssueReporter {
 		Callback:  c,
 	}
 }
+
+// Returns a new report_manager.PageIssueReporter with a callback function that
+// checks if the page's redirect chain goes through URLs in domains that are not
+// allowed in the crawl.
+func NewRedirectExternalReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		for _, h := range pageReport.RedirectChain {
+			if h.External {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorRedirectExternal,
+		Callback:  c,
+	}
+}
+
+// Returns a new report_manager.PageIssueReporter with a callback function that
+// checks if the page's redirect chain was still redirecting when the crawler stopped
+// following it. Chains ending in a redirect loop are not reported.
+func NewRedirectTooLongReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if len(pageReport.RedirectChain) == 0 {
+			return false
+		}
+
+		last := pageReport.RedirectChain[len(pageReport.RedirectChain)-1]
+		if last.Location == "" {
+			return false
+		}
+
+		for _, h := range pageReport.RedirectChain {
+			if h.URL == last.Location {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorRedirectTooLong,
+		Callback:  c,
+	}
+}
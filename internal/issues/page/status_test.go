@@ -130,3 +130,105 @@ func TestStatus50xIssues(t *testing.T) {
 		t.Errorf("TestStatus50xIssues: reportsIssue should be true")
 	}
 }
+
+// Test the RedirectExternal reporter with a PageReport whose redirect chain stays in the
+// allowed domains. The reporter should not report the issue.
+func TestRedirectExternalNoIssues(t *testing.T) {
+	pageReport := &models.PageReport{
+		Crawled:    true,
+		StatusCode: 301,
+		RedirectChain: []models.RedirectHop{
+			{URL: "https://example.com/a", StatusCode: 301, Location: "https://example.com/b"},
+			{URL: "https://example.com/b", StatusCode: 200},
+		},
+	}
+
+	reporter := page.NewRedirectExternalReporter()
+	if reporter.ErrorType != errors.ErrorRedirectExternal {
+		t.Errorf("TestNoIssues: error type is not correct")
+	}
+
+	reportsIssue := reporter.Callback(pageReport, &html.Node{}, &http.Header{})
+
+	if reportsIssue == true {
+		t.Errorf("TestRedirectExternalNoIssues: reportsIssue should be false")
+	}
+}
+
+// Test the RedirectExternal reporter with a PageReport whose redirect chain leaves the
+// allowed domains. The reporter should report the issue.
+func TestRedirectExternalIssues(t *testing.T) {
+	pageReport := &models.PageReport{
+		Crawled:    true,
+		StatusCode: 301,
+		RedirectChain: []models.RedirectHop{
+			{URL: "https://example.com/a", StatusCode: 301, Location: "https://example.org/b"},
+			{URL: "https://example.org/b", StatusCode: 301, Location: "https://example.com/c", External: true},
+			{URL: "https://example.com/c", StatusCode: 200},
+		},
+	}
+
+	reporter := page.NewRedirectExternalReporter()
+	if reporter.ErrorType != errors.ErrorRedirectExternal {
+		t.Errorf("TestIssues: error type is not correct")
+	}
+
+	reportsIssue := reporter.Callback(pageReport, &html.Node{}, &http.Header{})
+
+	if reportsIssue == false {
+		t.Errorf("TestRedirectExternalIssues: reportsIssue should be true")
+	}
+}
+
+// Test the RedirectTooLong reporter with PageReports whose redirect chains end in a
+// page or in a redirect loop. The reporter should not report the issue.
+func TestRedirectTooLongNoIssues(t *testing.T) {
+	chains := [][]models.RedirectHop{
+		{
+			{URL: "https://example.com/a", StatusCode: 301, Location: "https://example.com/b"},
+			{URL: "https://example.com/b", StatusCode: 200},
+		},
+		{
+			{URL: "https://example.com/a", StatusCode: 301, Location: "https://example.com/b"},
+			{URL: "https://example.com/b", StatusCode: 301, Location: "https://example.com/a"},
+		},
+	}
+
+	reporter := page.NewRedirectTooLongReporter()
+	if reporter.ErrorType != errors.ErrorRedirectTooLong {
+		t.Errorf("TestNoIssues: error type is not correct")
+	}
+
+	for _, chain := range chains {
+		pageReport := &models.PageReport{Crawled: true, StatusCode: 301, RedirectChain: chain}
+		reportsIssue := reporter.Callback(pageReport, &html.Node{}, &http.Header{})
+
+		if reportsIssue == true {
+			t.Errorf("TestRedirectTooLongNoIssues: reportsIssue should be false")
+		}
+	}
+}
+
+// Test the RedirectTooLong reporter with a PageReport whose redirect chain was still
+// redirecting when the crawler stopped following it. The reporter should report the issue.
+func TestRedirectTooLongIssues(t *testing.T) {
+	pageReport := &models.PageReport{
+		Crawled:    true,
+		StatusCode: 301,
+		RedirectChain: []models.RedirectHop{
+			{URL: "https://example.com/a", StatusCode: 301, Location: "https://example.com/b"},
+			{URL: "https://example.com/b", StatusCode: 301, Location: "https://example.com/c"},
+		},
+	}
+
+	reporter := page.NewRedirectTooLongReporter()
+	if reporter.ErrorType != errors.ErrorRedirectTooLong {
+		t.Errorf("TestIssues: error type is not correct")
+	}
+
+	reportsIssue := reporter.Callback(pageReport, &html.Node{}, &http.Header{})
+
+	if reportsIssue == false {
+		t.Errorf("TestRedirectTooLongIssues: reportsIssue should be true")
+	}
+}
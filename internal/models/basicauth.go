@@ -1,6 +1,10 @@
 package models
 
+// BasicAuth holds the credentials entered before a crawl starts. They are never stored.
 type BasicAuth struct {
-	AuthUser string
-	AuthPass string
+	AuthUser  string // HTTP Basic Auth credentials.
+	AuthPass  string
+	LoginUser string // Login form credentials.
+	LoginPass string
+	Token     string // Bearer token.
 }
@@ -4,29 +4,45 @@ import (
 	"time"
 )
 
+// Reasons for a crawl to stop before crawling all the URLs.
+const (
+	StopReasonURLLimit    = "url_limit"
+	StopReasonMaxDuration = "max_duration"
+	StopReasonStopped     = "stopped"
+)
+
 type Crawl struct {
-	Id        int64
-	ProjectId int64
-	Crawling  bool
+	Id             int64
+	ProjectId      int64
+	Crawling       bool
+	Interrupted    bool     // The crawl was interrupted and can be resumed from its checkpoint.
+	ListMode       bool     // Only the URLs of an uploaded list were crawled, without following links.
+	StopReason     string   // The limit that stopped the crawl before crawling all the URLs, empty if it was complete.
+	StartURLs      []string // Additional URLs the crawl started from besides the project's URL.
+	RobotsOverride bool     // The project's custom robots.txt was used instead of the live file.
 
-	URL                   string
-	Start                 time.Time
-	End                   time.Time
-	TotalIssues           int
-	TotalURLs             int
-	IssuesEnd             time.Time
-	CriticalIssues        int
-	AlertIssues           int
-	WarningIssues         int
-	BlockedByRobotstxt    int // URLs blocked by robots.txt
-	Noindex               int // URLS with noindex attribute
-	SitemapExists         bool
-	SitemapIsBlocked      bool
-	RobotstxtExists       bool
-	InternalFollowLinks   int
-	InternalNoFollowLinks int
-	ExternalFollowLinks   int
-	ExternalNoFollowLinks int
-	SponsoredLinks        int
-	UGCLinks              int
+	URL                    string
+	Start                  time.Time
+	End                    time.Time
+	TotalIssues            int
+	TotalURLs              int
+	IssuesEnd              time.Time
+	CriticalIssues         int
+	AlertIssues            int
+	WarningIssues          int
+	BlockedByRobotstxt     int // URLs blocked by robots.txt
+	Noindex                int // URLS with noindex attribute
+	SitemapExists          bool
+	SitemapIsBlocked       bool
+	RobotstxtExists        bool
+	InternalFollowLinks    int
+	InternalNoFollowLinks  int
+	ExternalFollowLinks    int
+	ExternalNoFollowLinks  int
+	SponsoredLinks         int
+	UGCLinks               int
+	CrawlDelay             time.Duration // Effective delay between requests to the main host.
+	ExcludedByRule         int           // URLs excluded by the project's URL rules
+	ExceededMaxDepth       int           // URLs not crawled because of the project's max depth
+	ExceededDirectoryLimit int           // URLs not crawled because of the project's directory limit
 }
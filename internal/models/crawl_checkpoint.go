@@ -0,0 +1,17 @@
+package models
+
+// CrawlCheckpoint holds the state of an unfinished crawl so it can be resumed. When it is
+// saved it only holds the changes since the previous checkpoint.
+type CrawlCheckpoint struct {
+	Queue []CrawlQueueItem
+	Done  []string // URLs removed from the queue.
+	Seen  []string
+}
+
+// CrawlQueueItem is a pending URL of an unfinished crawl.
+type CrawlQueueItem struct {
+	URL          string
+	Depth        int
+	Method       int
+	IgnoreDomain bool
+}
@@ -0,0 +1,14 @@
+package models
+
+// Types of the lines in a diff.
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// DiffLine is a line in the diff between two page snapshots.
+type DiffLine struct {
+	Type string
+	Text string
+}
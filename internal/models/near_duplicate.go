@@ -0,0 +1,21 @@
+package models
+
+// NearDuplicateCluster is a group of pages with very similar text.
+type NearDuplicateCluster struct {
+	PageReports []PageReport
+	Pairs       []NearDuplicatePair // Pairs of similar pages sorted by similarity.
+}
+
+// NearDuplicatePair is a pair of pages in a near duplicate cluster.
+type NearDuplicatePair struct {
+	A          PageReport
+	B          PageReport
+	Similarity int // Percentage of equal bits in the fingerprints of the pages' text.
+}
+
+// NearDuplicatesView is the view with the near duplicate clusters of a crawl.
+type NearDuplicatesView struct {
+	ProjectView *ProjectView
+	Threshold   int
+	Clusters    []NearDuplicateCluster
+}
@@ -8,7 +8,9 @@ type PageReport struct {
 	Id                 int64
 	URL                string
 	ParsedURL          *url.URL
+	OriginalURL        string // The URL before it was normalized, empty if the normalization didn't change it.
 	RedirectURL        string
+	RedirectChain      []RedirectHop // Complete redirect chain starting with the page's URL.
 	Refresh            string
 	StatusCode         int
 	ContentType        string
@@ -26,7 +28,9 @@ type PageReport struct {
 	ExternalLinks      []Link
 	Words              int
 	Hreflangs          []Hreflang
-	Size               int64
+	Size               int64  // Size of the decoded body in bytes.
+	TransferSize       int64  // Bytes transferred over the network, compressed if the body has a content encoding.
+	ContentEncoding    string // Value of the Content-Encoding header.
 	Images             []Image
 	Scripts            []string
 	Styles             []string
@@ -39,6 +43,12 @@ type PageReport struct {
 	InternalLinks      []InternalLink
 	Depth              int
 	BodyHash           string
+	SimHash            uint64 // SimHash fingerprint of the page's visible text.
 	Timeout            bool
 	TTFB               int
+	Attempts           int // Number of requests made to crawl the URL, including the retries.
+	Headers            []ResponseHeader
+	Body               []byte // Response body, it is kept until the page report is saved.
+	UndecodedBody      bool   // The body's content encoding is not supported so the body was not parsed.
+	Snapshot           []byte // Compressed response body, only set if the page's snapshot is stored.
 }
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
@@ -17,4 +18,55 @@ type Project struct {
 	Deleting           bool
 	BasicAuth          bool
 	CheckExternalLinks bool
+	Workers            int     // Number of concurrent crawler workers.
+	MinDelay           int     // Minimum delay between requests in milliseconds.
+	MaxDelay           int     // Maximum delay between requests in milliseconds.
+	MaxRPS             float64 // Maximum requests per second for each host, zero means no limit.
+	CrawlDelay         int     // Overrides the robots.txt crawl delay in milliseconds, zero means no override.
+	URLRules           string  // Include and exclude URL rules, one per line.
+	StripParams        string  // Comma separated query parameters removed from the URLs, "*" is a wildcard.
+	SortParams         bool    // Sort the URL query parameters by name.
+	DropFragment       bool    // Remove the fragment from the URLs.
+	LowercaseHost      bool    // Lowercase the URL hosts.
+	TrailingSlash      string  // Add or remove the trailing slash of the URL paths, empty keeps them unchanged.
+	MaxDepth           int     // Maximum crawl depth, zero means no limit.
+	MaxDirectoryURLs   int     // Maximum number of URLs crawled in each directory, zero means no limit.
+	Headers            string  // Custom request headers, one "Name: value" per line. Encrypted when stored.
+	Cookies            string  // Request cookies, one "name=value" per line. Encrypted when stored.
+	LoginURL           string  // URL of the login form page, empty if the project doesn't use a login form.
+	LoginUserField     string  // Name of the login form's username field.
+	LoginPassField     string  // Name of the login form's password field.
+	BearerAuth         bool    // Send a bearer token in the Authorization header.
+	RetryAttempts      int     // Max number of attempts for URLs failing with transient errors, one means no retries.
+	AdaptiveThrottle   bool    // Slow down the crawl when the server's response times or errors rise.
+	MaxURLs            int     // Maximum number of URLs crawled, zero means the configured limit.
+	MaxDuration        int     // Maximum crawl duration in minutes, zero means the configured limit.
+	StoreSnapshots     bool    // Store the compressed response bodies of the crawled pages.
+	StartURLs          string  // Additional URLs the crawl starts from, one per line.
+	RobotsOverride     string  // Custom robots.txt used instead of the live file of the project's host.
+	HostOverrides      string  // Addresses the hosts are crawled from instead of the resolved ones, in the /etc/hosts format.
+	LocalPath          string  // Local build directory crawled instead of the network, relative to the configured local directory.
+}
+
+// UsesRobotsOverride returns true if the crawler uses the project's custom robots.txt
+// instead of the live file. The override has no effect if robots.txt is ignored.
+func (p Project) UsesRobotsOverride() bool {
+	return p.RobotsOverride != "" && !p.IgnoreRobotsTxt
+}
+
+// StartURLList returns the project's additional start URLs, skipping the empty lines.
+func (p Project) StartURLList() []string {
+	urls := []string{}
+	for _, l := range strings.Split(p.StartURLs, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			urls = append(urls, l)
+		}
+	}
+
+	return urls
+}
+
+// RequiresCredentials returns true if credentials must be entered before crawling the project.
+func (p Project) RequiresCredentials() bool {
+	return p.BasicAuth || p.LoginURL != "" || p.BearerAuth
 }
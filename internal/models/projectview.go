@@ -1,6 +1,7 @@
 package models
 
 type ProjectView struct {
-	Project Project
-	Crawl   Crawl
+	Project  Project
+	Crawl    Crawl
+	Schedule Schedule // The project's crawl schedule, the ProjectId is zero if it has none.
 }
@@ -0,0 +1,9 @@
+package models
+
+type RedirectHop struct {
+	URL        string
+	StatusCode int    // -1 if the request failed.
+	Location   string // Empty if the hop doesn't redirect.
+	External   bool   // The hop's URL is not in the domains allowed in the crawl.
+	Blocked    bool   // The hop's URL is blocked by the robots.txt so it was not requested.
+}
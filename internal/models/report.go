@@ -10,6 +10,24 @@ type SchemeCount struct {
 	HTTPS int
 }
 
+// CompressionCount holds the number of compressed and uncompressed text responses
+// along with their decoded and transferred bytes.
+type CompressionCount struct {
+	Compressed   int
+	Uncompressed int
+	Size         int64
+	TransferSize int64
+}
+
+// Ratio returns the percentage of bytes saved by compressing the text responses.
+func (c *CompressionCount) Ratio() int {
+	if c.Size == 0 || c.TransferSize >= c.Size {
+		return 0
+	}
+
+	return int(100 - c.TransferSize*100/c.Size)
+}
+
 type AltCount struct {
 	Alt    int
 	NonAlt int
@@ -30,4 +48,6 @@ type PageReportView struct {
 	InLinks    []InternalLink
 	Redirects  []PageReport
 	Paginator  Paginator
+	Snapshot   string     // Source of the page's snapshot, empty if it wasn't stored.
+	Diff       []DiffLine // Differences with the snapshot of the previous crawl, nil if there's none.
 }
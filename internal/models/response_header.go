@@ -0,0 +1,13 @@
+package models
+
+type ResponseHeader struct {
+	Name  string
+	Value string
+}
+
+// HeaderFilter filters the page reports by their response headers.
+type HeaderFilter struct {
+	Name    string
+	Value   string // Text the header's value must contain, empty to match any value.
+	Missing bool   // Match the page reports without a matching header.
+}
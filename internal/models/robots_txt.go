@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+// RobotsTxt is the robots.txt file of a host requested during a crawl.
+type RobotsTxt struct {
+	CrawlId    int64
+	CrawlStart time.Time // Start of the crawl the file was requested in.
+	Host       string
+	URL        string
+	StatusCode int    // Status code of the response, zero if the request failed.
+	Body       string // Body of the file, empty unless the status code is 200.
+	Overridden bool   // The body is the project's override and the live file was not requested.
+}
+
+// RobotsTxtVersion is a version of a host's robots.txt file in the history of its crawls.
+type RobotsTxtVersion struct {
+	RobotsTxt
+	Changed bool // The file changed since the previous version.
+}
+
+// RobotsTest is the result of testing an URL against the stored robots.txt rules.
+type RobotsTest struct {
+	URL        string
+	Agent      string
+	Host       string
+	StatusCode int // Status code of the host's robots.txt file, all URLs are allowed unless it is 200.
+	Allowed    bool
+	Group      string // User agent of the group whose rules apply, empty if no group applies.
+	Line       int    // Line number of the matching rule, zero if no rule matches.
+	Rule       string
+	Error      string
+}
+
+// RobotsView is the view of the robots.txt files of a crawl.
+type RobotsView struct {
+	ProjectView *ProjectView
+	Hosts       []string
+	RobotsTxt   *RobotsTxt // Selected version of the host's file, nil if there are no files.
+	Previous    *RobotsTxt // Version of the file before the selected one, nil if there is none.
+	Diff        []DiffLine
+	History     []RobotsTxtVersion
+	Test        *RobotsTest
+	Agents      []string // Suggested user agents for the tester.
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// Schedule frequencies.
+const (
+	ScheduleDaily  = "daily"
+	ScheduleWeekly = "weekly"
+	ScheduleCron   = "cron"
+)
+
+// Schedule is a project's recurring crawl schedule.
+type Schedule struct {
+	ProjectId   int64
+	UserId      int    // Id of the project's user.
+	Frequency   string // One of "daily", "weekly" or "cron".
+	Time        string // Time of the daily and weekly crawls in "15:04" format.
+	Weekday     int    // Day of the weekly crawls, where zero is Sunday.
+	Cron        string // Cron expression used with the "cron" frequency.
+	WindowStart string // Start of the time window crawls are allowed to start in, empty for no window.
+	WindowEnd   string // End of the allowed time window, it can be earlier than the start to cross midnight.
+	NextRun     time.Time
+	LastRun     time.Time // Time of the last scheduled crawl, zero if it has never run.
+}
@@ -0,0 +1,9 @@
+package models
+
+type ThrottleMessage struct {
+	Level     int
+	Workers   int
+	Delay     int // Delay added before each request in milliseconds.
+	AvgTTFB   int
+	ErrorRate float64
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// Number of days before a TLS certificate expires it is reported as expiring.
+const CertificateExpiryDays = 30
+
+// TLSHost contains the TLS connection and certificate details of a crawled host.
+type TLSHost struct {
+	Host             string
+	URL              string // URL of the request that opened the connection.
+	Protocol         string // Negotiated protocol, "h2" or "http/1.1".
+	TLSVersion       string
+	CipherSuite      string
+	Subject          string
+	Issuer           string
+	SANs             []string  // Names the certificate is valid for.
+	Chain            []string  // Subjects of the certificates in the chain, starting with the leaf certificate.
+	NotAfter         time.Time // Expiry date of the certificate.
+	HostnameMismatch bool
+	ObsoleteVersion  bool
+	Error            string // Handshake error, empty if the handshake succeeded.
+}
+
+// Expiring returns true if the certificate expires within CertificateExpiryDays days of t.
+func (h TLSHost) Expiring(t time.Time) bool {
+	return !h.NotAfter.IsZero() && h.NotAfter.Before(t.AddDate(0, 0, CertificateExpiryDays))
+}
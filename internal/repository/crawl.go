@@ -15,11 +15,18 @@ type CrawlRepository struct {
 }
 
 // SaveCrawl inserts a new crawl into the database and returns a new Crawl model with
-// the data provided by the project.
-func (ds *CrawlRepository) SaveCrawl(p models.Project) (*models.Crawl, error) {
-	stmt, _ := ds.DB.Prepare("INSERT INTO crawls (project_id) VALUES (?)")
+// the data provided by the project. The listMode parameter sets if the crawl only audits
+// an uploaded list of URLs, in that case the project's start URLs are not used. The crawl
+// records if the project's robots.txt override is used.
+func (ds *CrawlRepository) SaveCrawl(p models.Project, listMode bool) (*models.Crawl, error) {
+	startURLs := []string{}
+	if !listMode {
+		startURLs = p.StartURLList()
+	}
+
+	stmt, _ := ds.DB.Prepare("INSERT INTO crawls (project_id, list_mode, start_urls, robots_override) VALUES (?, ?, ?, ?)")
 	defer stmt.Close()
-	res, err := stmt.Exec(p.Id)
+	res, err := stmt.Exec(p.Id, listMode, strings.Join(startURLs, "\n"), p.UsesRobotsOverride())
 
 	if err != nil {
 		return nil, err
@@ -31,10 +38,13 @@ func (ds *CrawlRepository) SaveCrawl(p models.Project) (*models.Crawl, error) {
 	}
 
 	return &models.Crawl{
-		Id:        cid,
-		ProjectId: p.Id,
-		URL:       p.URL,
-		Start:     time.Now(),
+		Id:             cid,
+		ProjectId:      p.Id,
+		URL:            p.URL,
+		Start:          time.Now(),
+		ListMode:       listMode,
+		StartURLs:      startURLs,
+		RobotsOverride: p.UsesRobotsOverride(),
 	}, nil
 }
 
@@ -59,7 +69,18 @@ func (ds *CrawlRepository) GetLastCrawl(p *models.Project) models.Crawl {
 			links_external_follow,
 			links_external_nofollow,
 			links_sponsored,
-			links_ugc
+			links_ugc,
+			crawl_delay,
+			blocked_by_robotstxt,
+			noindex,
+			interrupted,
+			excluded_by_rule,
+			exceeded_max_depth,
+			exceeded_directory_limit,
+			list_mode,
+			stop_reason,
+			IFNULL(start_urls, ''),
+			robots_override
 		FROM crawls
 		WHERE project_id = ?
 		ORDER BY start DESC LIMIT 1`
@@ -67,6 +88,8 @@ func (ds *CrawlRepository) GetLastCrawl(p *models.Project) models.Crawl {
 	row := ds.DB.QueryRow(query, p.Id)
 
 	var endTime, issuesEndTime sql.NullTime
+	var crawlDelay int64
+	var startURLs string
 	crawl := models.Crawl{Crawling: true}
 	err := row.Scan(
 		&crawl.Id,
@@ -87,17 +110,37 @@ func (ds *CrawlRepository) GetLastCrawl(p *models.Project) models.Crawl {
 		&crawl.ExternalNoFollowLinks,
 		&crawl.SponsoredLinks,
 		&crawl.UGCLinks,
+		&crawlDelay,
+		&crawl.BlockedByRobotstxt,
+		&crawl.Noindex,
+		&crawl.Interrupted,
+		&crawl.ExcludedByRule,
+		&crawl.ExceededMaxDepth,
+		&crawl.ExceededDirectoryLimit,
+		&crawl.ListMode,
+		&crawl.StopReason,
+		&startURLs,
+		&crawl.RobotsOverride,
 	)
 	if err != nil && err != sql.ErrNoRows {
 		log.Printf("GetLastCrawl project id %d: %v\n", p.Id, err)
 	}
 
+	crawl.StartURLs = strings.Fields(startURLs)
+
+	crawl.CrawlDelay = time.Duration(crawlDelay) * time.Millisecond
+	crawl.ProjectId = p.Id
+
 	if endTime.Valid && issuesEndTime.Valid {
 		crawl.End = endTime.Time
 		crawl.IssuesEnd = issuesEndTime.Time
 		crawl.Crawling = false
 	}
 
+	if crawl.Interrupted {
+		crawl.Crawling = false
+	}
+
 	return crawl
 }
 
@@ -116,7 +159,8 @@ func (ds *CrawlRepository) GetLastCrawls(p models.Project, limit int) []models.C
 			alert_issues,
 			warning_issues,
 			blocked_by_robotstxt,
-			noindex
+			noindex,
+			interrupted
 		FROM crawls
 		WHERE project_id = ?
 		ORDER BY start DESC LIMIT ?`
@@ -143,10 +187,14 @@ func (ds *CrawlRepository) GetLastCrawls(p models.Project, limit int) []models.C
 			&crawl.WarningIssues,
 			&crawl.BlockedByRobotstxt,
 			&crawl.Noindex,
+			&crawl.Interrupted,
 		)
 		if err != nil {
 			log.Printf("GetLastCrawl: %v\n", err)
 		}
+		if crawl.Interrupted {
+			crawl.Crawling = false
+		}
 		if endTime.Valid && issuesEndTime.Valid {
 			crawl.End = endTime.Time
 			crawl.IssuesEnd = issuesEndTime.Time
@@ -193,6 +241,11 @@ func (ds *CrawlRepository) DeleteCrawlData(crawl *models.Crawl) {
 	deleteFunc(crawl.Id, "iframes")
 	deleteFunc(crawl.Id, "audios")
 	deleteFunc(crawl.Id, "videos")
+	deleteFunc(crawl.Id, "redirect_hops")
+	deleteFunc(crawl.Id, "response_headers")
+	deleteFunc(crawl.Id, "tls_hosts")
+	deleteFunc(crawl.Id, "crawl_queue")
+	deleteFunc(crawl.Id, "crawl_seen")
 	deleteFunc(crawl.Id, "pagereports")
 }
 
@@ -227,14 +280,15 @@ func (ds *CrawlRepository) DeleteProjectCrawls(p *models.Project) {
 	}
 }
 
-// Deletes all crawls that are unfinished and have the issues_end field set to null.
+// Deletes all crawls that are unfinished and have the issues_end field set to null,
+// except for the interrupted crawls that can be resumed.
 // It cleans up the crawl data for each unfinished crawl before deleting it.
 func (ds *CrawlRepository) DeleteUnfinishedCrawls() {
 	query := `
 		SELECT
 			crawls.id
 		FROM crawls
-		WHERE crawls.issues_end IS NULL
+		WHERE crawls.issues_end IS NULL AND crawls.interrupted = 0
 	`
 	count := 0
 
@@ -293,11 +347,16 @@ func (ds *CrawlRepository) UpdateCrawl(crawl *models.Crawl) {
 			links_external_nofollow = ?,
 			links_sponsored = ?,
 			links_ugc = ?,
+			crawl_delay = ?,
+			excluded_by_rule = ?,
+			exceeded_max_depth = ?,
+			exceeded_directory_limit = ?,
 			issues_end = ?,
 			critical_issues = ?,
 			alert_issues = ?,
 			warning_issues = ?,
-			total_issues = ?
+			total_issues = ?,
+			stop_reason = ?
 		WHERE id = ?`
 
 	_, err := ds.DB.Exec(
@@ -315,11 +374,16 @@ func (ds *CrawlRepository) UpdateCrawl(crawl *models.Crawl) {
 		crawl.ExternalNoFollowLinks,
 		crawl.SponsoredLinks,
 		crawl.UGCLinks,
+		crawl.CrawlDelay.Milliseconds(),
+		crawl.ExcludedByRule,
+		crawl.ExceededMaxDepth,
+		crawl.ExceededDirectoryLimit,
 		crawl.IssuesEnd,
 		crawl.CriticalIssues,
 		crawl.AlertIssues,
 		crawl.WarningIssues,
 		crawl.TotalIssues,
+		crawl.StopReason,
 		crawl.Id,
 	)
 	if err != nil {
@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// Max number of rows inserted in a single statement when saving a checkpoint.
+const checkpointBatchSize = 500
+
+// SaveCrawlCheckpoint updates the crawl's checkpoint with the changes in the CrawlCheckpoint.
+// The queued and seen URLs are appended to the checkpoint and the done URLs are removed from
+// its queue, so the cost of a checkpoint depends on the changes and not on the crawl's size.
+// It also stores the crawl's progress so far, so the crawl can be resumed with the right
+// totals, and the id of the last page report saved.
+func (ds *CrawlRepository) SaveCrawlCheckpoint(crawl *models.Crawl, cp *models.CrawlCheckpoint) error {
+	tx, err := ds.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i := 0; i < len(cp.Done); i += checkpointBatchSize {
+		batch := cp.Done[i:min(i+checkpointBatchSize, len(cp.Done))]
+		placeholders := []string{}
+		v := []interface{}{crawl.Id}
+		for _, u := range batch {
+			placeholders = append(placeholders, "?")
+			v = append(v, Hash(u))
+		}
+
+		query := "DELETE FROM crawl_queue WHERE crawl_id = ? AND url_hash IN (" + strings.Join(placeholders, ",") + ")"
+		if _, err := tx.Exec(query, v...); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(cp.Queue); i += checkpointBatchSize {
+		batch := cp.Queue[i:min(i+checkpointBatchSize, len(cp.Queue))]
+		placeholders := []string{}
+		v := []interface{}{}
+		for _, q := range batch {
+			placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?)")
+			v = append(v, crawl.Id, q.URL, Hash(q.URL), q.Depth, q.Method, q.IgnoreDomain)
+		}
+
+		query := "INSERT INTO crawl_queue (crawl_id, url, url_hash, depth, method, ignore_domain) VALUES " + strings.Join(placeholders, ",")
+		if _, err := tx.Exec(query, v...); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(cp.Seen); i += checkpointBatchSize {
+		batch := cp.Seen[i:min(i+checkpointBatchSize, len(cp.Seen))]
+		placeholders := []string{}
+		v := []interface{}{}
+		for _, u := range batch {
+			placeholders = append(placeholders, "(?, ?)")
+			v = append(v, crawl.Id, u)
+		}
+
+		query := "INSERT INTO crawl_seen (crawl_id, url) VALUES " + strings.Join(placeholders, ",")
+		if _, err := tx.Exec(query, v...); err != nil {
+			return err
+		}
+	}
+
+	query := `UPDATE
+		crawls
+		SET
+			checkpoint = NOW(),
+			checkpoint_pagereport = (SELECT IFNULL(MAX(id), 0) FROM pagereports WHERE crawl_id = ?),
+			total_urls = ?,
+			blocked_by_robotstxt = ?,
+			noindex = ?,
+			links_internal_follow = ?,
+			links_internal_nofollow = ?,
+			links_external_follow = ?,
+			links_external_nofollow = ?,
+			links_sponsored = ?,
+			links_ugc = ?,
+			excluded_by_rule = ?,
+			exceeded_max_depth = ?,
+			exceeded_directory_limit = ?
+		WHERE id = ?`
+
+	_, err = tx.Exec(
+		query,
+		crawl.Id,
+		crawl.TotalURLs,
+		crawl.BlockedByRobotstxt,
+		crawl.Noindex,
+		crawl.InternalFollowLinks,
+		crawl.InternalNoFollowLinks,
+		crawl.ExternalFollowLinks,
+		crawl.ExternalNoFollowLinks,
+		crawl.SponsoredLinks,
+		crawl.UGCLinks,
+		crawl.ExcludedByRule,
+		crawl.ExceededMaxDepth,
+		crawl.ExceededDirectoryLimit,
+		crawl.Id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// FindCheckpointQueue returns a channel with the pending URLs stored in the crawl's checkpoint
+// in the same order they were queued.
+func (ds *CrawlRepository) FindCheckpointQueue(crawl *models.Crawl) <-chan models.CrawlQueueItem {
+	stream := make(chan models.CrawlQueueItem)
+
+	go func() {
+		defer close(stream)
+
+		rows, err := ds.DB.Query("SELECT url, depth, method, ignore_domain FROM crawl_queue WHERE crawl_id = ? ORDER BY id", crawl.Id)
+		if err != nil {
+			log.Printf("FindCheckpointQueue: cid %d %v\n", crawl.Id, err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			q := models.CrawlQueueItem{}
+			if err := rows.Scan(&q.URL, &q.Depth, &q.Method, &q.IgnoreDomain); err != nil {
+				log.Printf("FindCheckpointQueue: %v\n", err)
+				continue
+			}
+
+			stream <- q
+		}
+	}()
+
+	return stream
+}
+
+// FindCheckpointSeen returns a channel with the seen URLs stored in the crawl's checkpoint.
+func (ds *CrawlRepository) FindCheckpointSeen(crawl *models.Crawl) <-chan string {
+	stream := make(chan string)
+
+	go func() {
+		defer close(stream)
+
+		rows, err := ds.DB.Query("SELECT url FROM crawl_seen WHERE crawl_id = ?", crawl.Id)
+		if err != nil {
+			log.Printf("FindCheckpointSeen: cid %d %v\n", crawl.Id, err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var u string
+			if err := rows.Scan(&u); err != nil {
+				log.Printf("FindCheckpointSeen: %v\n", err)
+				continue
+			}
+
+			stream <- u
+		}
+	}()
+
+	return stream
+}
+
+// DeleteCrawlCheckpoint removes the crawl's checkpoint once it is no longer needed.
+func (ds *CrawlRepository) DeleteCrawlCheckpoint(crawl *models.Crawl) {
+	for _, table := range []string{"crawl_queue", "crawl_seen"} {
+		if _, err := ds.DB.Exec("DELETE FROM "+table+" WHERE crawl_id = ?", crawl.Id); err != nil {
+			log.Printf("DeleteCrawlCheckpoint: cid %d table %s %v\n", crawl.Id, table, err)
+		}
+	}
+
+	if _, err := ds.DB.Exec("UPDATE crawls SET checkpoint = NULL, interrupted = 0 WHERE id = ?", crawl.Id); err != nil {
+		log.Printf("DeleteCrawlCheckpoint: cid %d %v\n", crawl.Id, err)
+	}
+}
+
+// InterruptUnfinishedCrawls marks the unfinished crawls that have a checkpoint as interrupted,
+// so they can be resumed instead of being deleted.
+func (ds *CrawlRepository) InterruptUnfinishedCrawls() {
+	query := `UPDATE crawls SET interrupted = 1 WHERE end IS NULL AND checkpoint IS NOT NULL`
+
+	res, err := ds.DB.Exec(query)
+	if err != nil {
+		log.Printf("InterruptUnfinishedCrawls: %v\n", err)
+		return
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		log.Printf("Found %d interrupted crawls.", n)
+	}
+}
+
+// ResumeInterruptedCrawl clears the crawl's interrupted flag once it has been resumed.
+// The page reports saved after the last checkpoint are deleted, as their URLs are still
+// pending in the checkpoint and will be crawled again.
+func (ds *CrawlRepository) ResumeInterruptedCrawl(crawl *models.Crawl) {
+	query := `
+		DELETE pagereports FROM pagereports
+		INNER JOIN crawls ON crawls.id = pagereports.crawl_id
+		WHERE crawls.id = ? AND pagereports.id > crawls.checkpoint_pagereport`
+
+	if _, err := ds.DB.Exec(query, crawl.Id); err != nil {
+		log.Printf("ResumeInterruptedCrawl: delete cid %d %v\n", crawl.Id, err)
+	}
+
+	query = `
+		DELETE snapshots FROM snapshots
+		INNER JOIN crawls ON crawls.id = snapshots.crawl_id
+		WHERE crawls.id = ? AND snapshots.pagereport_id > crawls.checkpoint_pagereport`
+
+	if _, err := ds.DB.Exec(query, crawl.Id); err != nil {
+		log.Printf("ResumeInterruptedCrawl: delete snapshots cid %d %v\n", crawl.Id, err)
+	}
+
+	_, err := ds.DB.Exec("UPDATE crawls SET interrupted = 0 WHERE id = ?", crawl.Id)
+	if err != nil {
+		log.Printf("ResumeInterruptedCrawl: cid %d %v\n", crawl.Id, err)
+	}
+}
+
+// GetPreviousCrawl returns the project's crawl that was started before the specified crawl.
+func (ds *CrawlRepository) GetPreviousCrawl(crawl *models.Crawl) models.Crawl {
+	query := `
+		SELECT
+			id
+		FROM crawls
+		WHERE project_id = ? AND id < ?
+		ORDER BY id DESC LIMIT 1`
+
+	previous := models.Crawl{}
+	row := ds.DB.QueryRow(query, crawl.ProjectId, crawl.Id)
+	if err := row.Scan(&previous.Id); err != nil && err != sql.ErrNoRows {
+		log.Printf("GetPreviousCrawl: cid %d %v\n", crawl.Id, err)
+	}
+
+	previous.ProjectId = crawl.ProjectId
+
+	return previous
+}
+
+// DeleteCrawl deletes the crawl and all of its data.
+func (ds *CrawlRepository) DeleteCrawl(crawl *models.Crawl) {
+	ds.DeleteCrawlData(crawl)
+
+	if _, err := ds.DB.Exec("DELETE FROM crawls WHERE id = ?", crawl.Id); err != nil {
+		log.Printf("DeleteCrawl: cid %d %v\n", crawl.Id, err)
+	}
+}
@@ -130,6 +130,32 @@ func (ds *DashboardRepository) CountScheme(cid int64) *models.SchemeCount {
 	return c
 }
 
+// CountCompression returns a CompressionCount model with the number of compressed and uncompressed
+// successful text responses, and their total decoded and transferred bytes.
+func (ds *DashboardRepository) CountCompression(cid int64) *models.CompressionCount {
+	query := `
+		SELECT
+			COALESCE(SUM(CASE WHEN content_encoding NOT IN ('', 'identity') THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN content_encoding IN ('', 'identity') THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(size), 0),
+			COALESCE(SUM(transfer_size), 0)
+		FROM pagereports
+		WHERE crawl_id = ? AND crawled = 1 AND status_code BETWEEN 200 AND 299
+			AND (media_type LIKE 'text/%' OR media_type LIKE '%+xml' OR media_type LIKE '%+json'
+				OR media_type IN ('application/javascript', 'application/json', 'application/xml'))
+	`
+
+	c := &models.CompressionCount{}
+
+	row := ds.DB.QueryRow(query, cid)
+	err := row.Scan(&c.Compressed, &c.Uncompressed, &c.Size, &c.TransferSize)
+	if err != nil {
+		log.Println(err)
+	}
+
+	return c
+}
+
 // CountByMediaType returns a CountList model with the total number of pagereports by media type.
 func (ds *DashboardRepository) CountByMediaType(cid int64) *models.CountList {
 	query := `
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"log"
 	"math"
+	"strings"
 
 	"github.com/stjudewashere/seonaut/internal/models"
 )
@@ -27,6 +28,7 @@ func (ds *PageReportRepository) SavePageReport(r *models.PageReport, cid int64)
 			url,
 			url_hash,
 			scheme,
+			host,
 			redirect_url,
 			redirect_hash,
 			refresh,
@@ -48,9 +50,14 @@ func (ds *PageReportRepository) SavePageReport(r *models.PageReport, cid int64)
 			in_sitemap,
 			depth,
 			body_hash,
-			ttfb
+			ttfb,
+			original_url,
+			attempts,
+			transfer_size,
+			content_encoding,
+			simhash
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	stmt, err := ds.DB.Prepare(query)
 	if err != nil {
@@ -63,6 +70,7 @@ func (ds *PageReportRepository) SavePageReport(r *models.PageReport, cid int64)
 		r.URL,
 		urlHash,
 		r.ParsedURL.Scheme,
+		Truncate(r.ParsedURL.Host, 256),
 		r.RedirectURL,
 		redirectHash,
 		r.Refresh,
@@ -85,6 +93,11 @@ func (ds *PageReportRepository) SavePageReport(r *models.PageReport, cid int64)
 		r.Depth,
 		r.BodyHash,
 		r.TTFB,
+		Truncate(r.OriginalURL, 2048),
+		r.Attempts,
+		r.TransferSize,
+		Truncate(r.ContentEncoding, 32),
+		int64(r.SimHash), // The fingerprint is stored in a signed BIGINT column.
 	)
 	if err != nil {
 		return r, err
@@ -105,6 +118,9 @@ func (ds *PageReportRepository) SavePageReport(r *models.PageReport, cid int64)
 		ds.SavePageReportVideos,
 		ds.SavePageReportScripts,
 		ds.SavePageReportStyles,
+		ds.SavePageReportRedirectHops,
+		ds.SavePageReportHeaders,
+		ds.SavePageReportSnapshot,
 	}
 
 	for _, sf := range f {
@@ -184,6 +200,46 @@ func (ds *PageReportRepository) SavePageReportHreflangs(r *models.PageReport, ci
 	return err
 }
 
+// Save pagereport redirect chain hops.
+func (ds *PageReportRepository) SavePageReportRedirectHops(r *models.PageReport, cid int64) error {
+	if len(r.RedirectChain) == 0 {
+		return nil
+	}
+
+	sqlString := "INSERT INTO redirect_hops (pagereport_id, crawl_id, url, status_code, location, external, blocked) values "
+	v := []interface{}{}
+	for _, h := range r.RedirectChain {
+		sqlString += "(?, ?, ?, ?, ?, ?, ?),"
+		v = append(v, r.Id, cid, Truncate(h.URL, 2048), h.StatusCode, Truncate(h.Location, 2048), h.External, h.Blocked)
+	}
+	sqlString = sqlString[0 : len(sqlString)-1]
+	stmt, _ := ds.DB.Prepare(sqlString)
+	defer stmt.Close()
+
+	_, err := stmt.Exec(v...)
+	return err
+}
+
+// Save pagereport response headers.
+func (ds *PageReportRepository) SavePageReportHeaders(r *models.PageReport, cid int64) error {
+	if len(r.Headers) == 0 {
+		return nil
+	}
+
+	sqlString := "INSERT INTO response_headers (pagereport_id, crawl_id, name, value) values "
+	v := []interface{}{}
+	for _, h := range r.Headers {
+		sqlString += "(?, ?, ?, ?),"
+		v = append(v, r.Id, cid, Truncate(h.Name, 256), Truncate(h.Value, 2048))
+	}
+	sqlString = sqlString[0 : len(sqlString)-1]
+	stmt, _ := ds.DB.Prepare(sqlString)
+	defer stmt.Close()
+
+	_, err := stmt.Exec(v...)
+	return err
+}
+
 // Save pagereport images.
 func (ds *PageReportRepository) SavePageReportImages(r *models.PageReport, cid int64) error {
 	if len(r.Images) == 0 {
@@ -499,7 +555,11 @@ func (ds *PageReportRepository) FindPageReportById(rid int) models.PageReport {
 			in_sitemap,
 			depth,
 			body_hash,
-			ttfb
+			ttfb,
+			original_url,
+			attempts,
+			transfer_size,
+			content_encoding
 		FROM pagereports
 		WHERE id = ?`
 
@@ -529,6 +589,10 @@ func (ds *PageReportRepository) FindPageReportById(rid int) models.PageReport {
 		&p.Depth,
 		&p.BodyHash,
 		&p.TTFB,
+		&p.OriginalURL,
+		&p.Attempts,
+		&p.TransferSize,
+		&p.ContentEncoding,
 	)
 	if err != nil {
 		log.Println(err)
@@ -560,6 +624,93 @@ func (ds *PageReportRepository) FindPageReportHreflangs(pageReport *models.PageR
 	return hreflangs
 }
 
+// Find the redirect chain hops of an specific pagereport.
+func (ds *PageReportRepository) FindPageReportRedirectHops(pageReport *models.PageReport, cid int64) []models.RedirectHop {
+	hops := []models.RedirectHop{}
+
+	rows, err := ds.DB.Query("SELECT url, status_code, location, external, blocked FROM redirect_hops WHERE pagereport_id = ? ORDER BY id", pageReport.Id)
+	if err != nil {
+		log.Println(err)
+		return hops
+	}
+
+	for rows.Next() {
+		h := models.RedirectHop{}
+		err = rows.Scan(&h.URL, &h.StatusCode, &h.Location, &h.External, &h.Blocked)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		hops = append(hops, h)
+	}
+
+	return hops
+}
+
+// Find the response headers of an specific pagereport.
+func (ds *PageReportRepository) FindPageReportHeaders(pageReport *models.PageReport, cid int64) []models.ResponseHeader {
+	headers := []models.ResponseHeader{}
+
+	rows, err := ds.DB.Query("SELECT name, value FROM response_headers WHERE pagereport_id = ? ORDER BY id", pageReport.Id)
+	if err != nil {
+		log.Println(err)
+		return headers
+	}
+
+	for rows.Next() {
+		h := models.ResponseHeader{}
+		err = rows.Scan(&h.Name, &h.Value)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		headers = append(headers, h)
+	}
+
+	return headers
+}
+
+// FindSimHashes returns the successful HTML page reports of a crawl with their SimHash fingerprints.
+// Only one of the pages with identical content is included.
+func (ds *PageReportRepository) FindSimHashes(cid int64) []models.PageReport {
+	pageReports := []models.PageReport{}
+
+	query := `
+		SELECT id, url, title, simhash
+		FROM pagereports
+		WHERE id IN (
+			SELECT MIN(id)
+			FROM pagereports
+			WHERE crawl_id = ? AND media_type = "text/html" AND body_hash <> "" AND simhash <> 0
+				AND status_code >= 200 AND status_code < 300
+			GROUP BY body_hash, simhash
+		)`
+
+	rows, err := ds.DB.Query(query, cid)
+	if err != nil {
+		log.Println(err)
+		return pageReports
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p := models.PageReport{}
+		var hash int64
+		err = rows.Scan(&p.Id, &p.URL, &p.Title, &hash)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		p.SimHash = uint64(hash)
+		pageReports = append(pageReports, p)
+	}
+
+	return pageReports
+}
+
 // Find images in an specific pagereport.
 func (ds *PageReportRepository) FindPageReportImages(pageReport *models.PageReport, cid int64) []models.Image {
 	images := []models.Image{}
@@ -825,8 +976,9 @@ func (ds *PageReportRepository) FindSitemapPageReports(cid int64) <-chan *models
 
 // FindPaginatedPageReports returns a paginated slice of models.PageReport.
 // The page to be retrieved is specidied in the "p" parameter. This method also allows for
-// "term" search in case it is not an empty string "".
-func (ds *PageReportRepository) FindPaginatedPageReports(cid int64, p int, term string) []models.PageReport {
+// "term" search in case it is not an empty string "", and for filtering by response header
+// in case hf is not nil.
+func (ds *PageReportRepository) FindPaginatedPageReports(cid int64, p int, term string, hf *models.HeaderFilter) []models.PageReport {
 	max := paginationMax
 	offset := max * (p - 1)
 	args := []interface{}{term, cid}
@@ -847,6 +999,12 @@ func (ds *PageReportRepository) FindPaginatedPageReports(cid int64, p int, term
 		args = append(args, term)
 	}
 
+	if hf != nil {
+		condition, headerArgs := headerFilterCondition(hf)
+		query += condition
+		args = append(args, headerArgs...)
+	}
+
 	query += `
 		ORDER BY exact_match DESC, url ASC
 		LIMIT ?, ?`
@@ -876,7 +1034,7 @@ func (ds *PageReportRepository) FindPaginatedPageReports(cid int64, p int, term
 
 // GetNumberOfPagesForPageReport returns the total number of pageReport pages.
 // This method can be used to build a paginator.
-func (ds *PageReportRepository) GetNumberOfPagesForPageReport(cid int64, term string) int {
+func (ds *PageReportRepository) GetNumberOfPagesForPageReport(cid int64, term string, hf *models.HeaderFilter) int {
 	query := `
 		SELECT count(id)
 		FROM pagereports
@@ -889,6 +1047,12 @@ func (ds *PageReportRepository) GetNumberOfPagesForPageReport(cid int64, term st
 		args = append(args, term)
 	}
 
+	if hf != nil {
+		condition, headerArgs := headerFilterCondition(hf)
+		query += condition
+		args = append(args, headerArgs...)
+	}
+
 	row := ds.DB.QueryRow(query, args...)
 	var c int
 	if err := row.Scan(&c); err != nil {
@@ -1051,3 +1215,23 @@ func (ds *PageReportRepository) GetNumberOfPagesForRedirecting(pageReport *model
 	return int(math.Ceil(f))
 
 }
+
+// headerFilterCondition returns the SQL condition and arguments to filter the pagereports
+// by response header. The header value is matched if it contains the filter's value.
+func headerFilterCondition(hf *models.HeaderFilter) (string, []interface{}) {
+	condition := ` AND EXISTS (`
+	if hf.Missing {
+		condition = ` AND NOT EXISTS (`
+	}
+
+	condition += `SELECT 1 FROM response_headers WHERE response_headers.pagereport_id = pagereports.id AND response_headers.name = ?`
+	args := []interface{}{hf.Name}
+
+	if hf.Value != "" {
+		condition += ` AND response_headers.value LIKE ?`
+		v := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(hf.Value)
+		args = append(args, "%"+v+"%")
+	}
+
+	return condition + `)`, args
+}
@@ -23,9 +23,37 @@ func (ds *ProjectRepository) SaveProject(project *models.Project, uid int) {
 			allow_subdomains,
 			basic_auth,
 			user_id,
-			check_external_links
+			check_external_links,
+			workers,
+			min_delay,
+			max_delay,
+			max_rps,
+			crawl_delay,
+			url_rules,
+			strip_params,
+			sort_params,
+			drop_fragment,
+			lowercase_host,
+			max_depth,
+			max_directory_urls,
+			headers,
+			cookies,
+			login_url,
+			login_user_field,
+			login_pass_field,
+			bearer_auth,
+			retry_attempts,
+			adaptive_throttle,
+			max_urls,
+			max_duration,
+			store_snapshots,
+			start_urls,
+			robots_override,
+			host_overrides,
+			local_path,
+			trailing_slash
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	stmt, _ := ds.DB.Prepare(query)
@@ -40,6 +68,34 @@ func (ds *ProjectRepository) SaveProject(project *models.Project, uid int) {
 		project.BasicAuth,
 		uid,
 		project.CheckExternalLinks,
+		project.Workers,
+		project.MinDelay,
+		project.MaxDelay,
+		project.MaxRPS,
+		project.CrawlDelay,
+		project.URLRules,
+		project.StripParams,
+		project.SortParams,
+		project.DropFragment,
+		project.LowercaseHost,
+		project.MaxDepth,
+		project.MaxDirectoryURLs,
+		project.Headers,
+		project.Cookies,
+		project.LoginURL,
+		project.LoginUserField,
+		project.LoginPassField,
+		project.BearerAuth,
+		project.RetryAttempts,
+		project.AdaptiveThrottle,
+		project.MaxURLs,
+		project.MaxDuration,
+		project.StoreSnapshots,
+		project.StartURLs,
+		project.RobotsOverride,
+		project.HostOverrides,
+		project.LocalPath,
+		project.TrailingSlash,
 	)
 	if err != nil {
 		log.Printf("saveProject: %v\n", err)
@@ -61,7 +117,35 @@ func (ds *ProjectRepository) FindProjectsByUser(uid int) []models.Project {
 			basic_auth,
 			deleting,
 			created,
-			check_external_links
+			check_external_links,
+			workers,
+			min_delay,
+			max_delay,
+			max_rps,
+			crawl_delay,
+			IFNULL(url_rules, ''),
+			IFNULL(strip_params, ''),
+			sort_params,
+			drop_fragment,
+			lowercase_host,
+			max_depth,
+			max_directory_urls,
+			IFNULL(headers, ''),
+			IFNULL(cookies, ''),
+			login_url,
+			login_user_field,
+			login_pass_field,
+			bearer_auth,
+			retry_attempts,
+			adaptive_throttle,
+			max_urls,
+			max_duration,
+			store_snapshots,
+			IFNULL(start_urls, ''),
+			IFNULL(robots_override, ''),
+			IFNULL(host_overrides, ''),
+			IFNULL(local_path, ''),
+			trailing_slash
 		FROM projects
 		WHERE user_id = ?
 		ORDER BY url ASC`
@@ -86,6 +170,34 @@ func (ds *ProjectRepository) FindProjectsByUser(uid int) []models.Project {
 			&p.Deleting,
 			&p.Created,
 			&p.CheckExternalLinks,
+			&p.Workers,
+			&p.MinDelay,
+			&p.MaxDelay,
+			&p.MaxRPS,
+			&p.CrawlDelay,
+			&p.URLRules,
+			&p.StripParams,
+			&p.SortParams,
+			&p.DropFragment,
+			&p.LowercaseHost,
+			&p.MaxDepth,
+			&p.MaxDirectoryURLs,
+			&p.Headers,
+			&p.Cookies,
+			&p.LoginURL,
+			&p.LoginUserField,
+			&p.LoginPassField,
+			&p.BearerAuth,
+			&p.RetryAttempts,
+			&p.AdaptiveThrottle,
+			&p.MaxURLs,
+			&p.MaxDuration,
+			&p.StoreSnapshots,
+			&p.StartURLs,
+			&p.RobotsOverride,
+			&p.HostOverrides,
+			&p.LocalPath,
+			&p.TrailingSlash,
 		)
 		if err != nil {
 			log.Println(err)
@@ -112,7 +224,35 @@ func (ds *ProjectRepository) FindProjectById(id int, uid int) (models.Project, e
 			basic_auth,
 			deleting,
 			created,
-			check_external_links
+			check_external_links,
+			workers,
+			min_delay,
+			max_delay,
+			max_rps,
+			crawl_delay,
+			IFNULL(url_rules, ''),
+			IFNULL(strip_params, ''),
+			sort_params,
+			drop_fragment,
+			lowercase_host,
+			max_depth,
+			max_directory_urls,
+			IFNULL(headers, ''),
+			IFNULL(cookies, ''),
+			login_url,
+			login_user_field,
+			login_pass_field,
+			bearer_auth,
+			retry_attempts,
+			adaptive_throttle,
+			max_urls,
+			max_duration,
+			store_snapshots,
+			IFNULL(start_urls, ''),
+			IFNULL(robots_override, ''),
+			IFNULL(host_overrides, ''),
+			IFNULL(local_path, ''),
+			trailing_slash
 		FROM projects
 		WHERE id = ? AND user_id = ?`
 
@@ -131,6 +271,34 @@ func (ds *ProjectRepository) FindProjectById(id int, uid int) (models.Project, e
 		&p.Deleting,
 		&p.Created,
 		&p.CheckExternalLinks,
+		&p.Workers,
+		&p.MinDelay,
+		&p.MaxDelay,
+		&p.MaxRPS,
+		&p.CrawlDelay,
+		&p.URLRules,
+		&p.StripParams,
+		&p.SortParams,
+		&p.DropFragment,
+		&p.LowercaseHost,
+		&p.MaxDepth,
+		&p.MaxDirectoryURLs,
+		&p.Headers,
+		&p.Cookies,
+		&p.LoginURL,
+		&p.LoginUserField,
+		&p.LoginPassField,
+		&p.BearerAuth,
+		&p.RetryAttempts,
+		&p.AdaptiveThrottle,
+		&p.MaxURLs,
+		&p.MaxDuration,
+		&p.StoreSnapshots,
+		&p.StartURLs,
+		&p.RobotsOverride,
+		&p.HostOverrides,
+		&p.LocalPath,
+		&p.TrailingSlash,
 	)
 	if err != nil {
 		log.Println(err)
@@ -169,7 +337,35 @@ func (ds *ProjectRepository) UpdateProject(p *models.Project) error {
 			crawl_sitemap = ?,
 			allow_subdomains = ?,
 			basic_auth = ?,
-			check_external_links = ?
+			check_external_links = ?,
+			workers = ?,
+			min_delay = ?,
+			max_delay = ?,
+			max_rps = ?,
+			crawl_delay = ?,
+			url_rules = ?,
+			strip_params = ?,
+			sort_params = ?,
+			drop_fragment = ?,
+			lowercase_host = ?,
+			max_depth = ?,
+			max_directory_urls = ?,
+			headers = ?,
+			cookies = ?,
+			login_url = ?,
+			login_user_field = ?,
+			login_pass_field = ?,
+			bearer_auth = ?,
+			retry_attempts = ?,
+			adaptive_throttle = ?,
+			max_urls = ?,
+			max_duration = ?,
+			store_snapshots = ?,
+			start_urls = ?,
+			robots_override = ?,
+			host_overrides = ?,
+			local_path = ?,
+			trailing_slash = ?
 		WHERE id = ?
 	`
 	_, err := ds.DB.Exec(
@@ -181,6 +377,34 @@ func (ds *ProjectRepository) UpdateProject(p *models.Project) error {
 		p.AllowSubdomains,
 		p.BasicAuth,
 		p.CheckExternalLinks,
+		p.Workers,
+		p.MinDelay,
+		p.MaxDelay,
+		p.MaxRPS,
+		p.CrawlDelay,
+		p.URLRules,
+		p.StripParams,
+		p.SortParams,
+		p.DropFragment,
+		p.LowercaseHost,
+		p.MaxDepth,
+		p.MaxDirectoryURLs,
+		p.Headers,
+		p.Cookies,
+		p.LoginURL,
+		p.LoginUserField,
+		p.LoginPassField,
+		p.BearerAuth,
+		p.RetryAttempts,
+		p.AdaptiveThrottle,
+		p.MaxURLs,
+		p.MaxDuration,
+		p.StoreSnapshots,
+		p.StartURLs,
+		p.RobotsOverride,
+		p.HostOverrides,
+		p.LocalPath,
+		p.TrailingSlash,
 		p.Id,
 	)
 
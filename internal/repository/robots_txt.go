@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// SaveRobotsTxt stores the robots.txt files of the crawl's hosts.
+func (ds *CrawlRepository) SaveRobotsTxt(cid int64, files []models.RobotsTxt) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	sqlString := "INSERT INTO robots_txt (crawl_id, host, url, status_code, body, overridden) VALUES "
+	v := []interface{}{}
+	for _, f := range files {
+		sqlString += "(?, ?, ?, ?, ?, ?),"
+		v = append(v, cid, Truncate(f.Host, 256), Truncate(f.URL, 2048), f.StatusCode, f.Body, f.Overridden)
+	}
+	sqlString = sqlString[0 : len(sqlString)-1]
+
+	_, err := ds.DB.Exec(sqlString, v...)
+
+	return err
+}
+
+// FindRobotsTxt returns the robots.txt files of the crawl's hosts sorted by host.
+func (ds *CrawlRepository) FindRobotsTxt(cid int64) []models.RobotsTxt {
+	query := `
+		SELECT robots_txt.crawl_id, crawls.start, host, url, status_code, body, overridden
+		FROM robots_txt
+		INNER JOIN crawls ON crawls.id = robots_txt.crawl_id
+		WHERE robots_txt.crawl_id = ?
+		ORDER BY host`
+
+	return ds.robotsTxtQuery(query, cid)
+}
+
+// FindRobotsTxtHistory returns the versions of the host's robots.txt file in the project's
+// last crawls, starting with the most recent one.
+func (ds *CrawlRepository) FindRobotsTxtHistory(pid int64, host string, limit int) []models.RobotsTxt {
+	query := `
+		SELECT robots_txt.crawl_id, crawls.start, host, url, status_code, body, overridden
+		FROM robots_txt
+		INNER JOIN crawls ON crawls.id = robots_txt.crawl_id
+		WHERE crawls.project_id = ? AND robots_txt.host = ?
+		ORDER BY crawls.start DESC
+		LIMIT ?`
+
+	return ds.robotsTxtQuery(query, pid, host, limit)
+}
+
+// robotsTxtQuery runs a query returning robots.txt files.
+func (ds *CrawlRepository) robotsTxtQuery(query string, args ...interface{}) []models.RobotsTxt {
+	files := []models.RobotsTxt{}
+
+	rows, err := ds.DB.Query(query, args...)
+	if err != nil {
+		log.Printf("robotsTxtQuery: %v\n", err)
+		return files
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		f := models.RobotsTxt{}
+		var body sql.NullString
+		err := rows.Scan(&f.CrawlId, &f.CrawlStart, &f.Host, &f.URL, &f.StatusCode, &body, &f.Overridden)
+		if err != nil {
+			log.Printf("robotsTxtQuery: %v\n", err)
+			continue
+		}
+
+		f.Body = body.String
+		files = append(files, f)
+	}
+
+	return files
+}
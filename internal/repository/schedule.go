@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+type ScheduleRepository struct {
+	DB *sql.DB
+}
+
+// SaveSchedule stores the project's crawl schedule, replacing the existing one if there's any.
+func (ds *ScheduleRepository) SaveSchedule(s *models.Schedule) error {
+	query := `
+		INSERT INTO schedules (
+			project_id,
+			frequency,
+			time,
+			weekday,
+			cron,
+			window_start,
+			window_end,
+			next_run
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			frequency = VALUES(frequency),
+			time = VALUES(time),
+			weekday = VALUES(weekday),
+			cron = VALUES(cron),
+			window_start = VALUES(window_start),
+			window_end = VALUES(window_end),
+			next_run = VALUES(next_run)
+	`
+
+	_, err := ds.DB.Exec(
+		query,
+		s.ProjectId,
+		s.Frequency,
+		s.Time,
+		s.Weekday,
+		s.Cron,
+		s.WindowStart,
+		s.WindowEnd,
+		nullTime(s.NextRun),
+	)
+
+	return err
+}
+
+// FindScheduleByProject returns the crawl schedule of a project.
+func (ds *ScheduleRepository) FindScheduleByProject(pid int64) (models.Schedule, error) {
+	query := `
+		SELECT
+			schedules.project_id,
+			projects.user_id,
+			schedules.frequency,
+			schedules.time,
+			schedules.weekday,
+			schedules.cron,
+			schedules.window_start,
+			schedules.window_end,
+			schedules.next_run,
+			schedules.last_run
+		FROM schedules
+		INNER JOIN projects ON projects.id = schedules.project_id
+		WHERE schedules.project_id = ?`
+
+	s, err := scanSchedule(ds.DB.QueryRow(query, pid))
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("FindScheduleByProject: pid %d %v\n", pid, err)
+	}
+
+	return s, err
+}
+
+// FindDueSchedules returns the schedules of the projects that are due to be crawled at time t.
+// Projects being deleted are not included.
+func (ds *ScheduleRepository) FindDueSchedules(t time.Time) []models.Schedule {
+	schedules := []models.Schedule{}
+	query := `
+		SELECT
+			schedules.project_id,
+			projects.user_id,
+			schedules.frequency,
+			schedules.time,
+			schedules.weekday,
+			schedules.cron,
+			schedules.window_start,
+			schedules.window_end,
+			schedules.next_run,
+			schedules.last_run
+		FROM schedules
+		INNER JOIN projects ON projects.id = schedules.project_id
+		WHERE schedules.next_run <= ? AND projects.deleting = 0
+		ORDER BY schedules.next_run`
+
+	rows, err := ds.DB.Query(query, t)
+	if err != nil {
+		log.Printf("FindDueSchedules: %v\n", err)
+		return schedules
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s, err := scanSchedule(rows)
+		if err != nil {
+			log.Printf("FindDueSchedules: %v\n", err)
+			continue
+		}
+
+		schedules = append(schedules, s)
+	}
+
+	return schedules
+}
+
+// UpdateScheduleRuns updates the next and last run times of a schedule.
+func (ds *ScheduleRepository) UpdateScheduleRuns(s *models.Schedule) {
+	query := `UPDATE schedules SET next_run = ?, last_run = ? WHERE project_id = ?`
+	_, err := ds.DB.Exec(query, nullTime(s.NextRun), nullTime(s.LastRun), s.ProjectId)
+	if err != nil {
+		log.Printf("UpdateScheduleRuns: pid %d %v\n", s.ProjectId, err)
+	}
+}
+
+// DeleteSchedule removes the project's crawl schedule.
+func (ds *ScheduleRepository) DeleteSchedule(pid int64) {
+	_, err := ds.DB.Exec(`DELETE FROM schedules WHERE project_id = ?`, pid)
+	if err != nil {
+		log.Printf("DeleteSchedule: pid %d %v\n", pid, err)
+	}
+}
+
+// scanSchedule scans a schedule from a row.
+func scanSchedule(row interface{ Scan(...any) error }) (models.Schedule, error) {
+	s := models.Schedule{}
+	var nextRun, lastRun sql.NullTime
+	err := row.Scan(
+		&s.ProjectId,
+		&s.UserId,
+		&s.Frequency,
+		&s.Time,
+		&s.Weekday,
+		&s.Cron,
+		&s.WindowStart,
+		&s.WindowEnd,
+		&nextRun,
+		&lastRun,
+	)
+
+	s.NextRun = nextRun.Time
+	s.LastRun = lastRun.Time
+
+	return s, err
+}
+
+// nullTime returns a NullTime that is not valid if t is the zero time.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
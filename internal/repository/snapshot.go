@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"log"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// Save the pagereport's compressed snapshot if it has one.
+func (ds *PageReportRepository) SavePageReportSnapshot(r *models.PageReport, cid int64) error {
+	if len(r.Snapshot) == 0 {
+		return nil
+	}
+
+	query := "INSERT INTO snapshots (pagereport_id, crawl_id, url_hash, size, body) VALUES (?, ?, ?, ?, ?)"
+	_, err := ds.DB.Exec(query, r.Id, cid, Hash(r.URL), len(r.Snapshot), r.Snapshot)
+
+	return err
+}
+
+// CountSnapshotsSize returns the size in bytes of the compressed snapshots stored in the crawl.
+func (ds *PageReportRepository) CountSnapshotsSize(cid int64) int64 {
+	row := ds.DB.QueryRow("SELECT IFNULL(SUM(size), 0) FROM snapshots WHERE crawl_id = ?", cid)
+
+	var size int64
+	if err := row.Scan(&size); err != nil {
+		log.Printf("CountSnapshotsSize: cid %d %v\n", cid, err)
+	}
+
+	return size
+}
+
+// FindPageReportSnapshot returns the compressed snapshot of the pagereport, or nil if the
+// pagereport doesn't have one.
+func (ds *PageReportRepository) FindPageReportSnapshot(pageReport *models.PageReport, cid int64) []byte {
+	query := "SELECT body FROM snapshots WHERE pagereport_id = ? AND crawl_id = ? ORDER BY id DESC LIMIT 1"
+
+	var body []byte
+	if err := ds.DB.QueryRow(query, pageReport.Id, cid).Scan(&body); err != nil {
+		return nil
+	}
+
+	return body
+}
+
+// FindPreviousSnapshot returns the compressed snapshot of the pagereport's URL in the project's
+// previous crawl, or nil if there's none.
+func (ds *PageReportRepository) FindPreviousSnapshot(pageReport *models.PageReport, cid int64) []byte {
+	query := `
+		SELECT
+			snapshots.body
+		FROM snapshots
+		INNER JOIN crawls ON crawls.id = snapshots.crawl_id
+		WHERE snapshots.url_hash = ? AND snapshots.crawl_id < ?
+			AND crawls.project_id = (SELECT project_id FROM crawls WHERE id = ?)
+		ORDER BY snapshots.crawl_id DESC, snapshots.id DESC
+		LIMIT 1`
+
+	var body []byte
+	if err := ds.DB.QueryRow(query, Hash(pageReport.URL), cid, cid).Scan(&body); err != nil {
+		return nil
+	}
+
+	return body
+}
+
+// DeleteOldSnapshots deletes the snapshots of the project's crawls, except the ones of the
+// crawl and its previous crawl, which are kept to compare the pages between crawls.
+func (ds *CrawlRepository) DeleteOldSnapshots(crawl, previous *models.Crawl) {
+	query := `
+		DELETE snapshots FROM snapshots
+		INNER JOIN crawls ON crawls.id = snapshots.crawl_id
+		WHERE crawls.project_id = ? AND crawls.id NOT IN (?, ?)`
+
+	if _, err := ds.DB.Exec(query, crawl.ProjectId, crawl.Id, previous.Id); err != nil {
+		log.Printf("DeleteOldSnapshots: cid %d %v\n", crawl.Id, err)
+	}
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// SaveTLSHosts stores the TLS connection details of the crawl's hosts.
+func (ds *CrawlRepository) SaveTLSHosts(cid int64, hosts []models.TLSHost) error {
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	sqlString := `
+		INSERT INTO tls_hosts (
+			crawl_id,
+			host,
+			url,
+			protocol,
+			tls_version,
+			cipher_suite,
+			subject,
+			issuer,
+			sans,
+			chain,
+			not_after,
+			hostname_mismatch,
+			obsolete_version,
+			error
+		) VALUES `
+	v := []interface{}{}
+	for _, h := range hosts {
+		sqlString += "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?),"
+		v = append(
+			v,
+			cid,
+			Truncate(h.Host, 256),
+			Truncate(h.URL, 2048),
+			h.Protocol,
+			h.TLSVersion,
+			h.CipherSuite,
+			Truncate(h.Subject, 512),
+			Truncate(h.Issuer, 512),
+			strings.Join(h.SANs, "\n"),
+			strings.Join(h.Chain, "\n"),
+			nullTime(h.NotAfter),
+			h.HostnameMismatch,
+			h.ObsoleteVersion,
+			Truncate(h.Error, 1024),
+		)
+	}
+	sqlString = sqlString[0 : len(sqlString)-1]
+
+	_, err := ds.DB.Exec(sqlString, v...)
+
+	return err
+}
+
+// FindTLSHosts returns the TLS connection details of the crawl's hosts.
+func (ds *CrawlRepository) FindTLSHosts(cid int64) []models.TLSHost {
+	hosts := []models.TLSHost{}
+	query := `
+		SELECT
+			host,
+			url,
+			protocol,
+			tls_version,
+			cipher_suite,
+			subject,
+			issuer,
+			sans,
+			chain,
+			not_after,
+			hostname_mismatch,
+			obsolete_version,
+			error
+		FROM tls_hosts
+		WHERE crawl_id = ?
+		ORDER BY host`
+
+	rows, err := ds.DB.Query(query, cid)
+	if err != nil {
+		log.Printf("FindTLSHosts: cid %d %v\n", cid, err)
+		return hosts
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		h := models.TLSHost{}
+		var sans, chain sql.NullString
+		var notAfter sql.NullTime
+		err := rows.Scan(
+			&h.Host,
+			&h.URL,
+			&h.Protocol,
+			&h.TLSVersion,
+			&h.CipherSuite,
+			&h.Subject,
+			&h.Issuer,
+			&sans,
+			&chain,
+			&notAfter,
+			&h.HostnameMismatch,
+			&h.ObsoleteVersion,
+			&h.Error,
+		)
+		if err != nil {
+			log.Printf("FindTLSHosts: cid %d %v\n", cid, err)
+			continue
+		}
+
+		if sans.String != "" {
+			h.SANs = strings.Split(sans.String, "\n")
+		}
+
+		if chain.String != "" {
+			h.Chain = strings.Split(chain.String, "\n")
+		}
+
+		h.NotAfter = notAfter.Time
+		hosts = append(hosts, h)
+	}
+
+	return hosts
+}
@@ -28,18 +28,21 @@ func NewServer(container *services.Container) {
 	// Crawler routes
 	crawlHandler := crawlHandler{container}
 	http.HandleFunc("/crawl", container.CookieSession.Auth(crawlHandler.handleCrawl))
+	http.HandleFunc("/crawl/resume", container.CookieSession.Auth(crawlHandler.handleResumeCrawl))
 	http.HandleFunc("/crawl/stop", container.CookieSession.Auth(crawlHandler.handleStopCrawl))
 	http.HandleFunc("/crawl/live", container.CookieSession.Auth(crawlHandler.handleCrawlLive))
 	http.HandleFunc("/crawl/auth", container.CookieSession.Auth(crawlHandler.handleCrawlAuth))
+	http.HandleFunc("/crawl/list", container.CookieSession.Auth(crawlHandler.handleCrawlList))
 	http.HandleFunc("/crawl/ws", container.CookieSession.Auth(crawlHandler.handleCrawlWs))
 
 	// Dashboard route
 	dashboardHandler := dashboardHandler{container}
 	http.HandleFunc("/dashboard", container.CookieSession.Auth(dashboardHandler.handleDashboard))
 
-	// URL explorer route
+	// URL explorer routes
 	explorerHandler := explorerHandler{container}
 	http.HandleFunc("/explorer", container.CookieSession.Auth(explorerHandler.handleExplorer))
+	http.HandleFunc("/near-duplicates", container.CookieSession.Auth(explorerHandler.handleNearDuplicates))
 
 	// Data export routes
 	exportHandler := exportHandler{container}
@@ -59,6 +62,11 @@ func NewServer(container *services.Container) {
 	http.HandleFunc("/project/add", container.CookieSession.Auth(projectHandler.handleProjectAdd))
 	http.HandleFunc("/project/edit", container.CookieSession.Auth(projectHandler.handleProjectEdit))
 	http.HandleFunc("/project/delete", container.CookieSession.Auth(projectHandler.handleDeleteProject))
+	http.HandleFunc("/project/schedule", container.CookieSession.Auth(projectHandler.handleProjectSchedule))
+
+	// Robots.txt route
+	robotsHandler := robotsHandler{container}
+	http.HandleFunc("/robots", container.CookieSession.Auth(robotsHandler.handleRobots))
 
 	// Resource route
 	resourceHandler := resourceHandler{container}
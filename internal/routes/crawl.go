@@ -6,7 +6,9 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -20,6 +22,8 @@ const (
 	writeWait  = 10 * time.Second
 	pongWait   = 60 * time.Second
 	pingPeriod = (pongWait * 9) / 10
+
+	maxListSize = 10 << 20 // Max size in bytes of the uploaded URL lists.
 )
 
 type crawlHandler struct {
@@ -28,7 +32,7 @@ type crawlHandler struct {
 
 // handleCrawl handles the crawling of a project.
 // It expects a query parameter "pid" containing the project id to be crawled.
-// In case the project requieres BasicAuth it will redirect the user to the BasicAuth
+// In case the project requieres credentials it will redirect the user to the crawl
 // credentials URL. Otherwise, it starts a new crawler.
 func (h *crawlHandler) handleCrawl(w http.ResponseWriter, r *http.Request) {
 	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
@@ -49,8 +53,8 @@ func (h *crawlHandler) handleCrawl(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if p.BasicAuth {
-		http.Redirect(w, r, "/crawl/auth?id="+strconv.Itoa(pid), http.StatusSeeOther)
+	if p.RequiresCredentials() {
+		http.Redirect(w, r, "/crawl/auth?pid="+strconv.Itoa(pid), http.StatusSeeOther)
 		return
 	}
 
@@ -64,6 +68,44 @@ func (h *crawlHandler) handleCrawl(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/crawl/live?pid="+strconv.Itoa(pid), http.StatusSeeOther)
 }
 
+// handleResumeCrawl handles the resuming of a project's interrupted crawl.
+// It expects a query parameter "pid" containing the project id to be crawled.
+// In case the project requieres credentials it will redirect the user to the crawl
+// credentials URL. Otherwise, it resumes the crawl from its last checkpoint.
+func (h *crawlHandler) handleResumeCrawl(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	user, ok := h.CookieSession.GetUser(r.Context())
+	if !ok {
+		http.Redirect(w, r, "/signout", http.StatusSeeOther)
+		return
+	}
+
+	p, err := h.ProjectService.FindProject(pid, user.Id)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	if p.RequiresCredentials() {
+		http.Redirect(w, r, "/crawl/auth?resume=1&pid="+strconv.Itoa(pid), http.StatusSeeOther)
+		return
+	}
+
+	err = h.CrawlerService.ResumeCrawler(p, models.BasicAuth{})
+	if err != nil {
+		log.Printf("ResumeCrawler: %s %v\n", p.URL, err)
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, "/crawl/live?pid="+strconv.Itoa(pid), http.StatusSeeOther)
+}
+
 // handleStopCrawl handles the crawler stopping.
 // It expects a query paramater "pid" containinng the project id that is being crawled.
 // Aftar making sure the user owns the project it is stopped.
@@ -101,10 +143,12 @@ func (h *crawlHandler) handleStopCrawl(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/crawl/live?pid="+strconv.Itoa(pid), http.StatusSeeOther)
 }
 
-// handleCrawlAuth handles the crawling of a project with BasicAuth.
+// handleCrawlAuth handles the crawling of a project that requires credentials.
 // It expects a query parameter "pid" containing the project id to be crawled.
-// A form will be presented to the user to input the BasicAuth credentials, once the
-// form is submitted a crawler with BasicAuth is started.
+// A form will be presented to the user to input the BasicAuth, login form or bearer token
+// credentials, once the form is submitted a crawler using them is started. If the login
+// fails the form is presented again. If the "resume" query parameter is set the project's
+// interrupted crawl is resumed instead.
 // The function handles both GET and POST HTTP methods.
 // GET: Renders the auth form.
 // POST: Processes the auth form data and starts the crawler.
@@ -127,6 +171,9 @@ func (h *crawlHandler) handleCrawlAuth(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	resume := r.URL.Query().Get("resume") == "1"
+	loginError := false
+
 	if r.Method == http.MethodPost {
 		err := r.ParseForm()
 		if err != nil {
@@ -135,27 +182,132 @@ func (h *crawlHandler) handleCrawlAuth(w http.ResponseWriter, r *http.Request) {
 		}
 
 		basicAuth := models.BasicAuth{
-			AuthUser: r.FormValue("username"),
-			AuthPass: r.FormValue("password"),
+			AuthUser:  r.FormValue("username"),
+			AuthPass:  r.FormValue("password"),
+			LoginUser: r.FormValue("login_username"),
+			LoginPass: r.FormValue("login_password"),
+			Token:     r.FormValue("token"),
 		}
 
-		err = h.CrawlerService.StartCrawler(p, basicAuth)
-		if err != nil {
-			log.Printf("StartCrawler: %s %v\n", p.URL, err)
+		if resume {
+			err = h.CrawlerService.ResumeCrawler(p, basicAuth)
+		} else {
+			err = h.CrawlerService.StartCrawler(p, basicAuth)
+		}
+		if err == nil {
+			http.Redirect(w, r, "/crawl/live?pid="+strconv.Itoa(pid), http.StatusSeeOther)
 			return
 		}
 
-		http.Redirect(w, r, "/crawl/live?pid="+strconv.Itoa(pid), http.StatusSeeOther)
+		log.Printf("StartCrawler: %s %v\n", p.URL, err)
+		loginError = true
 	}
 
 	pageView := &PageView{
 		PageTitle: "CRAWL_AUTH_VIEW",
-		Data:      struct{ Project models.Project }{Project: p},
+		Data: struct {
+			Project models.Project
+			Resume  bool
+			Error   bool
+		}{Project: p, Resume: resume, Error: loginError},
 	}
 
 	h.Renderer.RenderTemplate(w, "crawl_auth", pageView)
 }
 
+// handleCrawlList handles the crawling of a list of URLs in list mode.
+// It expects a query parameter "pid" containing the project id to be crawled.
+// The URLs can be submitted in the "urls" textarea or uploaded as a text or CSV file,
+// along with any credentials the project requires. If the list is not valid or the
+// crawler can't be started the form is presented again with an error message.
+// The function handles both GET and POST HTTP methods.
+// GET: Renders the URL list form.
+// POST: Processes the URL list and starts the crawler.
+func (h *crawlHandler) handleCrawlList(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	user, ok := h.CookieSession.GetUser(r.Context())
+	if !ok {
+		http.Redirect(w, r, "/signout", http.StatusSeeOther)
+		return
+	}
+
+	p, err := h.ProjectService.FindProject(pid, user.Id)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	listError := ""
+
+	if r.Method == http.MethodPost {
+		urls, err := h.parseURLList(w, r, h.CrawlerService.CrawlLimit(p))
+		switch {
+		case err != nil:
+			log.Printf("ParseURLList: %s %v\n", p.URL, err)
+			listError = "The URL list could not be read."
+		case len(urls) == 0:
+			listError = "The list does not contain any valid URL."
+		default:
+			basicAuth := models.BasicAuth{
+				AuthUser:  r.FormValue("username"),
+				AuthPass:  r.FormValue("password"),
+				LoginUser: r.FormValue("login_username"),
+				LoginPass: r.FormValue("login_password"),
+				Token:     r.FormValue("token"),
+			}
+
+			err = h.CrawlerService.StartListCrawler(p, basicAuth, urls)
+			if err == nil {
+				http.Redirect(w, r, "/crawl/live?pid="+strconv.Itoa(pid), http.StatusSeeOther)
+				return
+			}
+
+			log.Printf("StartListCrawler: %s %v\n", p.URL, err)
+			listError = "The crawl could not be started. Please check the credentials and try again."
+		}
+	}
+
+	pageView := &PageView{
+		PageTitle: "CRAWL_LIST_VIEW",
+		User:      *user,
+		Data: struct {
+			Project models.Project
+			Error   string
+		}{Project: p, Error: listError},
+	}
+
+	h.Renderer.RenderTemplate(w, "crawl_list", pageView)
+}
+
+// parseURLList returns the URLs of the list submitted in the request. The uploaded file is used
+// if there is one, otherwise the URLs are read from the "urls" form field. Files with the .csv
+// extension are parsed as CSV files. The list is truncated at limit URLs.
+func (h *crawlHandler) parseURLList(w http.ResponseWriter, r *http.Request, limit int) ([]*url.URL, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxListSize)
+	if err := r.ParseMultipartForm(maxListSize); err != nil {
+		return nil, err
+	}
+
+	file, header, err := r.FormFile("file")
+	if err == http.ErrMissingFile {
+		return services.ParseURLList(strings.NewReader(r.FormValue("urls")), false, limit)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	isCSV := strings.EqualFold(filepath.Ext(header.Filename), ".csv")
+
+	return services.ParseURLList(file, isCSV, limit)
+}
+
 // handleCrawlLive handles the request for the live crawling of a project.
 // It expects a query parameter "pid" containing the project id to be crawled.
 // This handler renders a page that will connect via websockets to display the progress
@@ -276,6 +428,10 @@ func (h *crawlHandler) handleCrawlWs(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
+		if pubsubMessage.Name == "Throttle" {
+			wsMessage.Data = pubsubMessage.Data.(*models.ThrottleMessage)
+		}
+
 		if pubsubMessage.Name == "CrawlEnd" {
 			msg := pubsubMessage.Data.(int)
 			wsMessage.Data = msg
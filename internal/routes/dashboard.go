@@ -46,7 +46,9 @@ func (h *dashboardHandler) handleDashboard(w http.ResponseWriter, r *http.Reques
 		CanonicalCount    *models.CanonicalCount
 		AltCount          *models.AltCount
 		SchemeCount       *models.SchemeCount
+		CompressionCount  *models.CompressionCount
 		StatusCodeByDepth []models.StatusCodeByDepth
+		TLSHosts          []models.TLSHost
 	}{
 		ProjectView:       pv,
 		MediaChart:        h.DashboardService.GetMediaCount(pv.Crawl.Id),
@@ -55,7 +57,9 @@ func (h *dashboardHandler) handleDashboard(w http.ResponseWriter, r *http.Reques
 		CanonicalCount:    h.DashboardService.GetCanonicalCount(pv.Crawl.Id),
 		AltCount:          h.DashboardService.GetImageAltCount(pv.Crawl.Id),
 		SchemeCount:       h.DashboardService.GetSchemeCount(pv.Crawl.Id),
+		CompressionCount:  h.DashboardService.GetCompressionCount(pv.Crawl.Id),
 		StatusCodeByDepth: h.DashboardService.GetStatusCodeByDepth(pv.Crawl.Id),
+		TLSHosts:          h.CrawlerService.GetTLSHosts(&pv.Crawl),
 	}
 
 	pageView := &PageView{
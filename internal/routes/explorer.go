@@ -68,3 +68,39 @@ func (h *explorerHandler) handleExplorer(w http.ResponseWriter, r *http.Request)
 
 	h.Renderer.RenderTemplate(w, "explorer", v)
 }
+
+// handleNearDuplicates handles the view of the clusters of pages with similar text.
+// It expects a query parameter "pid" containing the project id.
+func (h *explorerHandler) handleNearDuplicates(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.CookieSession.GetUser(r.Context())
+	if !ok {
+		http.Redirect(w, r, "/signout", http.StatusSeeOther)
+		return
+	}
+
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	pv, err := h.ProjectViewService.GetProjectView(pid, user.Id)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	view := models.NearDuplicatesView{
+		ProjectView: pv,
+		Threshold:   h.ReportService.SimilarityThreshold(),
+		Clusters:    h.ReportService.GetNearDuplicates(pv.Crawl.Id),
+	}
+
+	v := &PageView{
+		Data:      view,
+		User:      *user,
+		PageTitle: "NEAR_DUPLICATES_VIEW",
+	}
+
+	h.Renderer.RenderTemplate(w, "near_duplicates", v)
+}
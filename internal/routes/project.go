@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"net/url"
@@ -187,10 +188,17 @@ func (h *projectHandler) handleProjectEdit(w http.ResponseWriter, r *http.Reques
 	}
 
 	data := &struct {
-		Project models.Project
-		Error   bool
+		Project           models.Project
+		Error             bool
+		SecretKeyRequired bool // The headers or cookies can't be saved without a secret key.
+		MaxURLs           int
+		MaxDuration       int
+		MaxSnapshots      int
 	}{
-		Project: p,
+		Project:      p,
+		MaxURLs:      h.CrawlerService.MaxURLs(),
+		MaxDuration:  h.CrawlerService.MaxDuration(),
+		MaxSnapshots: h.CrawlerService.MaxSnapshots(),
 	}
 
 	pageView := &PageView{
@@ -242,10 +250,105 @@ func (h *projectHandler) handleProjectEdit(w http.ResponseWriter, r *http.Reques
 			p.BasicAuth = false
 		}
 
+		p.Workers, err = strconv.Atoi(r.FormValue("workers"))
+		if err != nil {
+			p.Workers = services.DefaultWorkers
+		}
+
+		p.MinDelay, err = strconv.Atoi(r.FormValue("min_delay"))
+		if err != nil {
+			p.MinDelay = 0
+		}
+
+		p.MaxDelay, err = strconv.Atoi(r.FormValue("max_delay"))
+		if err != nil {
+			p.MaxDelay = services.DefaultMaxDelay
+		}
+
+		p.MaxRPS, err = strconv.ParseFloat(r.FormValue("max_rps"), 64)
+		if err != nil {
+			p.MaxRPS = 0
+		}
+
+		p.CrawlDelay, err = strconv.Atoi(r.FormValue("crawl_delay"))
+		if err != nil {
+			p.CrawlDelay = 0
+		}
+
+		p.RetryAttempts, err = strconv.Atoi(r.FormValue("retry_attempts"))
+		if err != nil {
+			p.RetryAttempts = services.DefaultRetryAttempts
+		}
+
+		p.AdaptiveThrottle, err = strconv.ParseBool(r.FormValue("adaptive_throttle"))
+		if err != nil {
+			p.AdaptiveThrottle = false
+		}
+
+		p.StoreSnapshots, err = strconv.ParseBool(r.FormValue("store_snapshots"))
+		if err != nil {
+			p.StoreSnapshots = false
+		}
+
+		p.MaxDepth, err = strconv.Atoi(r.FormValue("max_depth"))
+		if err != nil {
+			p.MaxDepth = 0
+		}
+
+		p.MaxDirectoryURLs, err = strconv.Atoi(r.FormValue("max_directory_urls"))
+		if err != nil {
+			p.MaxDirectoryURLs = 0
+		}
+
+		p.MaxURLs, err = strconv.Atoi(r.FormValue("max_urls"))
+		if err != nil {
+			p.MaxURLs = 0
+		}
+
+		p.MaxDuration, err = strconv.Atoi(r.FormValue("max_duration"))
+		if err != nil {
+			p.MaxDuration = 0
+		}
+
+		p.URLRules = strings.TrimSpace(r.FormValue("url_rules"))
+		p.StartURLs = strings.TrimSpace(r.FormValue("start_urls"))
+		p.RobotsOverride = strings.TrimSpace(r.FormValue("robots_override"))
+		p.HostOverrides = strings.TrimSpace(r.FormValue("host_overrides"))
+		p.LocalPath = strings.TrimSpace(r.FormValue("local_path"))
+		p.Headers = strings.TrimSpace(r.FormValue("headers"))
+		p.Cookies = strings.TrimSpace(r.FormValue("cookies"))
+		p.LoginURL = strings.TrimSpace(r.FormValue("login_url"))
+		p.LoginUserField = strings.TrimSpace(r.FormValue("login_user_field"))
+		p.LoginPassField = strings.TrimSpace(r.FormValue("login_pass_field"))
+
+		p.BearerAuth, err = strconv.ParseBool(r.FormValue("bearer_auth"))
+		if err != nil {
+			p.BearerAuth = false
+		}
+		p.StripParams = strings.TrimSpace(r.FormValue("strip_params"))
+
+		p.SortParams, err = strconv.ParseBool(r.FormValue("sort_params"))
+		if err != nil {
+			p.SortParams = false
+		}
+
+		p.DropFragment, err = strconv.ParseBool(r.FormValue("drop_fragment"))
+		if err != nil {
+			p.DropFragment = false
+		}
+
+		p.LowercaseHost, err = strconv.ParseBool(r.FormValue("lowercase_host"))
+		if err != nil {
+			p.LowercaseHost = false
+		}
+
+		p.TrailingSlash = r.FormValue("trailing_slash")
+
 		err = h.ProjectService.UpdateProject(&p)
 		if err != nil {
 			log.Printf("update project: %v", err)
 			data.Error = true
+			data.SecretKeyRequired = errors.Is(err, services.ErrNoSecretKey)
 			h.Renderer.RenderTemplate(w, "project_edit", pageView)
 			return
 		}
@@ -256,3 +359,90 @@ func (h *projectHandler) handleProjectEdit(w http.ResponseWriter, r *http.Reques
 
 	h.Renderer.RenderTemplate(w, "project_edit", pageView)
 }
+
+// handleProjectSchedule handles the edition of a project's crawl schedule.
+// It expects a query parameter "pid" containing the project id. If the schedule's
+// frequency is empty the project's schedule is removed.
+// This handler handles both, the GET and POST requests.
+func (h *projectHandler) handleProjectSchedule(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	user, ok := h.CookieSession.GetUser(r.Context())
+	if !ok {
+		http.Redirect(w, r, "/signout", http.StatusSeeOther)
+		return
+	}
+
+	p, err := h.ProjectService.FindProject(pid, user.Id)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	schedule, err := h.SchedulerService.FindSchedule(p)
+	if err != nil {
+		schedule = models.Schedule{Time: "00:00"}
+	}
+
+	data := &struct {
+		Project  models.Project
+		Schedule models.Schedule
+		Error    string
+	}{
+		Project:  p,
+		Schedule: schedule,
+	}
+
+	pageView := &PageView{
+		User:      *user,
+		PageTitle: "SCHEDULE_PROJECT",
+		Data:      data,
+	}
+
+	if r.Method == http.MethodPost {
+		err := r.ParseForm()
+		if err != nil {
+			log.Printf("handleProjectSchedule ParseForm: %v\n", err)
+			http.Redirect(w, r, "/", http.StatusSeeOther)
+			return
+		}
+
+		if r.FormValue("frequency") == "" {
+			h.SchedulerService.DeleteSchedule(p)
+			http.Redirect(w, r, "/", http.StatusSeeOther)
+			return
+		}
+
+		weekday, err := strconv.Atoi(r.FormValue("weekday"))
+		if err != nil {
+			weekday = 0
+		}
+
+		data.Schedule = models.Schedule{
+			ProjectId:   p.Id,
+			Frequency:   r.FormValue("frequency"),
+			Time:        r.FormValue("time"),
+			Weekday:     weekday,
+			Cron:        strings.TrimSpace(r.FormValue("cron")),
+			WindowStart: r.FormValue("window_start"),
+			WindowEnd:   r.FormValue("window_end"),
+		}
+
+		err = h.SchedulerService.SaveSchedule(&data.Schedule)
+		if err != nil {
+			log.Printf("save schedule: %v", err)
+			data.Error = err.Error()
+			h.Renderer.RenderTemplate(w, "project_schedule", pageView)
+			return
+		}
+
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	h.Renderer.RenderTemplate(w, "project_schedule", pageView)
+}
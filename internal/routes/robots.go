@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/stjudewashere/seonaut/internal/services"
+)
+
+type robotsHandler struct {
+	*services.Container
+}
+
+// handleRobots handles the view of the robots.txt files of the project's last crawl.
+// It expects the following query parameters:
+// - "pid" containing the project id.
+// - "host" the host of the robots.txt file, which defaults to the project's host.
+// - "v" the crawl id of the file's version, which defaults to the last crawl.
+// - "url" and "agent" the URL and user agent to be tested against the file's rules.
+func (h *robotsHandler) handleRobots(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.CookieSession.GetUser(r.Context())
+	if !ok {
+		http.Redirect(w, r, "/signout", http.StatusSeeOther)
+		return
+	}
+
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	pv, err := h.ProjectViewService.GetProjectView(pid, user.Id)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	version, err := strconv.ParseInt(r.URL.Query().Get("v"), 10, 64)
+	if err != nil {
+		version = 0
+	}
+
+	q := r.URL.Query()
+	view := h.RobotsService.GetRobotsView(pv, q.Get("host"), version, q.Get("url"), q.Get("agent"))
+
+	v := &PageView{
+		Data:      view,
+		User:      *user,
+		PageTitle: "ROBOTS_VIEW",
+	}
+
+	h.Renderer.RenderTemplate(w, "robots", v)
+}
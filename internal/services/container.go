@@ -26,10 +26,12 @@ type Container struct {
 	ReportManager      *ReportManager
 	UserService        *UserService
 	DashboardService   *DashboardService
+	RobotsService      *RobotsService
 	ProjectService     *ProjectService
 	ProjectViewService *ProjectViewService
 	ExportService      *Exporter
 	CrawlerService     *CrawlerService
+	SchedulerService   *SchedulerService
 	Renderer           *Renderer
 	CookieSession      *CookieSession
 
@@ -41,6 +43,7 @@ type Container struct {
 	exportRepository     *repository.ExportRepository
 	crawlRepository      *repository.CrawlRepository
 	dashboardRepository  *repository.DashboardRepository
+	scheduleRepository   *repository.ScheduleRepository
 }
 
 func NewContainer(configFile string) *Container {
@@ -54,10 +57,12 @@ func NewContainer(configFile string) *Container {
 	c.InitReportManager()
 	c.InitUserService()
 	c.InitDashboardService()
+	c.InitRobotsService()
 	c.InitProjectService()
 	c.InitProjectViewService()
 	c.InitExportService()
 	c.InitCrawlerService()
+	c.InitSchedulerService()
 	c.InitRenderer()
 	c.InitCookieSession()
 
@@ -105,8 +110,11 @@ func (c *Container) InitRepositories() {
 	c.exportRepository = &repository.ExportRepository{DB: c.db}
 	c.crawlRepository = &repository.CrawlRepository{DB: c.db}
 	c.dashboardRepository = &repository.DashboardRepository{DB: c.db}
+	c.scheduleRepository = &repository.ScheduleRepository{DB: c.db}
 
-	// Clean up unfinished crawls.
+	// Clean up unfinished crawls. The ones with a checkpoint are kept
+	// so they can be resumed.
+	c.crawlRepository.InterruptUnfinishedCrawls()
 	c.crawlRepository.DeleteUnfinishedCrawls()
 }
 
@@ -130,7 +138,7 @@ func (c *Container) InitReportService() {
 		c.issueRepository,
 	}
 
-	c.ReportService = NewReportService(storage)
+	c.ReportService = NewReportService(storage, c.similarityThreshold())
 }
 
 // Create the report manager and add all the available reporters.
@@ -141,10 +149,24 @@ func (c *Container) InitReportManager() {
 	}
 
 	// Create the sql multipage reporters and add them all to the reporterManager.
-	sqlReporters := multipage.NewSqlReporter(c.db)
+	sqlReporters := multipage.NewSqlReporter(c.db, c.similarityThreshold())
 	for _, r := range sqlReporters.GetAllReporters() {
 		c.ReportManager.AddMultipageReporter(r)
 	}
+
+	for _, r := range sqlReporters.GetListModeReporters() {
+		c.ReportManager.AddListModeReporter(r)
+	}
+}
+
+// similarityThreshold returns the configured min similarity of the near duplicate pages, from 0 to 1.
+func (c *Container) similarityThreshold() float64 {
+	threshold := c.Config.Crawler.SimilarityThreshold
+	if threshold <= 0 || threshold > 100 {
+		threshold = DefaultSimilarityThreshold
+	}
+
+	return float64(threshold) / 100
 }
 
 // Create the user service.
@@ -172,7 +194,7 @@ func (c *Container) InitProjectService() {
 		c.crawlRepository,
 	}
 
-	c.ProjectService = NewProjectService(storage)
+	c.ProjectService = NewProjectService(storage, NewEncrypter(c.Config.Crawler.SecretKey))
 }
 
 // Create the ProjectView service.
@@ -180,9 +202,11 @@ func (c *Container) InitProjectViewService() {
 	storage := &struct {
 		*repository.ProjectRepository
 		*repository.CrawlRepository
+		*repository.ScheduleRepository
 	}{
 		c.projectRepository,
 		c.crawlRepository,
+		c.scheduleRepository,
 	}
 
 	c.ProjectViewService = NewProjectViewService(storage)
@@ -223,11 +247,24 @@ func (c *Container) InitCrawlerService() {
 	c.CrawlerService = NewCrawlerService(storage, crawlerServices)
 }
 
+// Create the Scheduler service.
+func (c *Container) InitSchedulerService() {
+	c.SchedulerService = NewSchedulerService(c.scheduleRepository, SchedulerServicesContainer{
+		ProjectService: c.ProjectService,
+		CrawlerService: c.CrawlerService,
+	})
+}
+
 // Create the dashboCallbackBuilderard service.
 func (c *Container) InitDashboardService() {
 	c.DashboardService = NewDashboardService(c.dashboardRepository)
 }
 
+// Create the robots.txt service.
+func (c *Container) InitRobotsService() {
+	c.RobotsService = NewRobotsService(c.crawlRepository)
+}
+
 // Create html renderer.
 func (c *Container) InitRenderer() {
 	renderer, err := NewRenderer(&RendererConfig{
@@ -1,11 +1,15 @@
 package services
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/http/cookiejar"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -16,19 +20,44 @@ import (
 )
 
 const (
-	CrawlLimit      = 20000 // Max number of page reports that will be created
+	CrawlLimit      = 20000 // Max number of page reports that will be created if it is not configured.
 	LastCrawlsLimit = 5     // Max number returned by GetLastCrawls
 	ClientTimeout   = 10    // HTTP client timeout in seconds.
+	MaxRedirectHops = 10    // Max number of redirects followed in the redirect chains.
+
+	DefaultMaxDuration  = 120 // Max crawl duration in minutes if it is not configured.
+	DefaultMaxSnapshots = 100 // Max size of the crawl's compressed snapshots in MB if it is not configured.
+	CheckpointInterval  = 60  // Seconds between crawl checkpoints.
+
+	// Crawler storage that keeps the seen URLs and the queued requests on disk.
+	DiskStorage = "disk"
+
+	// URL rule excluding the logout URLs so the login session is kept during the crawl.
+	logoutURLRule = "exclude regex:(?i)(log|sign)[-_]?(out|off)"
 )
 
 type CrawlerServiceStorage interface {
-	SaveCrawl(models.Project) (*models.Crawl, error)
+	SaveCrawl(models.Project, bool) (*models.Crawl, error)
 	GetLastCrawl(p *models.Project) models.Crawl
 	GetLastCrawls(models.Project, int) []models.Crawl
 	DeleteCrawlData(c *models.Crawl)
 
 	CountIssuesByPriority(int64, int) int
 	UpdateCrawl(*models.Crawl)
+
+	SaveCrawlCheckpoint(*models.Crawl, *models.CrawlCheckpoint) error
+	FindCheckpointQueue(*models.Crawl) <-chan models.CrawlQueueItem
+	FindCheckpointSeen(*models.Crawl) <-chan string
+	DeleteCrawlCheckpoint(*models.Crawl)
+	ResumeInterruptedCrawl(*models.Crawl)
+	GetPreviousCrawl(*models.Crawl) models.Crawl
+	DeleteCrawl(*models.Crawl)
+
+	SaveTLSHosts(int64, []models.TLSHost) error
+	FindTLSHosts(int64) []models.TLSHost
+	SaveRobotsTxt(int64, []models.RobotsTxt) error
+
+	DeleteOldSnapshots(*models.Crawl, *models.Crawl)
 }
 
 type CrawlerServicesContainer struct {
@@ -61,16 +90,93 @@ func NewCrawlerService(s CrawlerServiceStorage, services CrawlerServicesContaine
 }
 
 // StartCrawler creates a new crawler and crawls the project's URL.
-// It adds a new crawler for the project, it returns an error if there's one already
+// It authenticates with the project's login form if it has one, and adds a new crawler
+// for the project. It returns an error if the login fails, if there's a crawler already
 // running or if there's an error creating it.
+// If the project's last crawl was interrupted it is discarded.
 // Finally the previous crawl's data is removed and the crawl is returned.
 func (s *CrawlerService) StartCrawler(p models.Project, b models.BasicAuth) error {
+	return s.startCrawler(p, b, nil)
+}
+
+// StartListCrawler creates a new crawler in list mode that crawls only the URLs in the list,
+// without following the links found in them. It returns an error if the list is empty or
+// if the crawler can't be started.
+func (s *CrawlerService) StartListCrawler(p models.Project, b models.BasicAuth, urls []*url.URL) error {
+	if len(urls) == 0 {
+		return errors.New("the URL list is empty")
+	}
+
+	return s.startCrawler(p, b, urls)
+}
+
+// startCrawler starts a new crawl of the project. If a list of URLs is provided the crawler
+// runs in list mode and only the listed URLs are crawled, otherwise it starts crawling
+// the project's URL.
+func (s *CrawlerService) startCrawler(p models.Project, b models.BasicAuth, urls []*url.URL) error {
+	listMode := len(urls) > 0
+
+	u, err := url.Parse(p.URL)
+	if err != nil {
+		return err
+	}
+
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	client, err := s.newClient(u, &p, &b)
+	if err != nil {
+		return err
+	}
+
+	// The crawler is created before the crawl is saved, so the crawl is not left unfinished
+	// and the interrupted crawl is not discarded if the crawler can't be created.
+	c, err := s.addCrawler(u, &p, client, listMode)
+	if err != nil {
+		return err
+	}
+
 	previousCrawl := s.store.GetLastCrawl(&p)
-	crawl, err := s.store.SaveCrawl(p)
+	if previousCrawl.Interrupted {
+		s.store.DeleteCrawl(&previousCrawl)
+		previousCrawl = s.store.GetLastCrawl(&p)
+	}
+
+	crawl, err := s.store.SaveCrawl(p, listMode)
 	if err != nil {
+		s.discardCrawler(&p, c)
 		return err
 	}
 
+	if listMode {
+		log.Printf("Crawling %d listed URLs of %s...", len(urls), p.URL)
+		for _, l := range urls {
+			c.AddRequest(&crawler.RequestMessage{URL: l, Depth: -1})
+		}
+	} else {
+		log.Printf("Crawling %s...", p.URL)
+		c.AddRequest(&crawler.RequestMessage{URL: u})
+		for _, s := range c.StartURLs() {
+			c.AddRequest(&crawler.RequestMessage{URL: s})
+		}
+	}
+
+	s.runCrawler(c, crawl, p, previousCrawl)
+
+	return nil
+}
+
+// ResumeCrawler resumes the project's last crawl from its checkpoint if it was interrupted.
+// It returns an error if the crawl can't be resumed.
+func (s *CrawlerService) ResumeCrawler(p models.Project, b models.BasicAuth) error {
+	crawl := s.store.GetLastCrawl(&p)
+	if !crawl.Interrupted {
+		return errors.New("the project's last crawl was not interrupted")
+	}
+
+	crawl.URL = p.URL
+
 	u, err := url.Parse(p.URL)
 	if err != nil {
 		return err
@@ -80,26 +186,78 @@ func (s *CrawlerService) StartCrawler(p models.Project, b models.BasicAuth) erro
 		u.Path = "/"
 	}
 
-	c, err := s.addCrawler(u, &p, &b)
+	client, err := s.newClient(u, &p, &b)
 	if err != nil {
 		return err
 	}
 
-	c.OnResponse(s.crawlerHandler.responseCallback(crawl, &p, c))
+	// The crawl is kept as interrupted, so it can be resumed later, if the crawler can't be created.
+	c, err := s.addCrawler(u, &p, client, crawl.ListMode)
+	if err != nil {
+		return err
+	}
 
-	go func() {
-		log.Printf("Crawling %s...", p.URL)
-		c.AddRequest(&crawler.RequestMessage{URL: u, Data: crawlerData{}})
+	// The checkpoint is streamed into the crawler so it is not loaded in memory at once.
+	c.Resume(crawl.TotalURLs)
+	for u := range s.store.FindCheckpointSeen(&crawl) {
+		c.ResumeSeen(u)
+	}
 
+	pending := 0
+	for q := range s.store.FindCheckpointQueue(&crawl) {
+		parsed, err := url.Parse(q.URL)
+		if err != nil {
+			continue
+		}
+
+		c.ResumePending(&crawler.RequestMessage{
+			URL:          parsed,
+			IgnoreDomain: q.IgnoreDomain,
+			Method:       crawler.Method(q.Method),
+			Depth:        q.Depth,
+		})
+		pending++
+	}
+
+	s.store.ResumeInterruptedCrawl(&crawl)
+
+	log.Printf("Resuming crawl of %s with %d pending URLs...", p.URL, pending)
+
+	previousCrawl := s.store.GetPreviousCrawl(&crawl)
+	s.runCrawler(c, &crawl, p, previousCrawl)
+
+	return nil
+}
+
+// runCrawler starts the crawler in a new goroutine, saving a checkpoint of the crawl periodically.
+// Once the crawler is done the crawl's multipage issues are created and the previous crawl's data
+// is removed.
+func (s *CrawlerService) runCrawler(c *crawler.Crawler, crawl *models.Crawl, p models.Project, previousCrawl models.Crawl) {
+	c.OnResponse(s.crawlerHandler.responseCallback(crawl, &p, c, int64(s.MaxSnapshots())*1024*1024))
+	c.OnCheckpoint(CheckpointInterval*time.Second, s.checkpointCallback(crawl))
+	c.OnThrottle(s.throttleCallback(p))
+
+	go func() {
 		// Calling Start() initiates the website crawling process and
 		// blocks execution until the crawling is complete.
 		c.Start()
+		s.store.DeleteCrawlCheckpoint(crawl)
 
 		crawl.RobotstxtExists = c.RobotstxtExists()
 		crawl.SitemapExists = c.SitemapExists()
 		crawl.SitemapIsBlocked = c.SitemapIsBlocked()
+		crawl.CrawlDelay = c.CrawlDelay()
+		crawl.StopReason = stopReason(c.StopReason())
 		crawl.End = time.Now()
 
+		if err := s.store.SaveTLSHosts(crawl.Id, tlsHosts(c.TLSHosts())); err != nil {
+			log.Printf("SaveTLSHosts: cid %d %v\n", crawl.Id, err)
+		}
+
+		if err := s.store.SaveRobotsTxt(crawl.Id, robotsTxt(c.RobotsFiles())); err != nil {
+			log.Printf("SaveRobotsTxt: cid %d %v\n", crawl.Id, err)
+		}
+
 		s.broker.Publish(fmt.Sprintf("crawl-%d", p.Id), &models.Message{Name: "IssuesInit"})
 		s.reportManager.CreateMultipageIssues(crawl)
 
@@ -115,9 +273,44 @@ func (s *CrawlerService) StartCrawler(p models.Project, b models.BasicAuth) erro
 
 		s.removeCrawler(&p)
 		s.store.DeleteCrawlData(&previousCrawl)
+		s.store.DeleteOldSnapshots(crawl, &previousCrawl)
 	}()
+}
 
-	return nil
+// checkpointCallback returns a callback that stores the changes in the crawler's checkpoint
+// along with the crawl's progress.
+func (s *CrawlerService) checkpointCallback(crawl *models.Crawl) crawler.CheckpointCallback {
+	return func(cp *crawler.Checkpoint) {
+		checkpoint := &models.CrawlCheckpoint{Seen: cp.Seen, Done: cp.Done}
+		for _, r := range cp.Pending {
+			checkpoint.Queue = append(checkpoint.Queue, models.CrawlQueueItem{
+				URL:          r.URL.String(),
+				Depth:        r.Depth,
+				Method:       int(r.Method),
+				IgnoreDomain: r.IgnoreDomain,
+			})
+		}
+
+		if err := s.store.SaveCrawlCheckpoint(crawl, checkpoint); err != nil {
+			log.Printf("SaveCrawlCheckpoint: cid %d %v\n", crawl.Id, err)
+		}
+	}
+}
+
+// throttleCallback returns a callback that publishes the adaptive throttle's state
+// in the project's crawl topic.
+func (s *CrawlerService) throttleCallback(p models.Project) crawler.ThrottleCallback {
+	return func(ts crawler.ThrottleState) {
+		log.Printf("Throttle level %d in %s: %d workers, %v delay", ts.Level, p.URL, ts.Workers, ts.Delay)
+
+		s.broker.Publish(fmt.Sprintf("crawl-%d", p.Id), &models.Message{Name: "Throttle", Data: &models.ThrottleMessage{
+			Level:     ts.Level,
+			Workers:   ts.Workers,
+			Delay:     int(ts.Delay.Milliseconds()),
+			AvgTTFB:   ts.AvgTTFB,
+			ErrorRate: ts.ErrorRate,
+		}})
+	}
 }
 
 // Get a slice with 'LastCrawlsLimit' number of the crawls
@@ -144,10 +337,127 @@ func (s *CrawlerService) StopCrawler(p models.Project) {
 	crawler.Stop()
 }
 
+// MaxURLs returns the configured upper limit for the number of URLs of the crawls.
+func (s *CrawlerService) MaxURLs() int {
+	if s.config.MaxURLs > 0 {
+		return s.config.MaxURLs
+	}
+
+	return CrawlLimit
+}
+
+// CrawlLimit returns the max number of URLs of the project's crawls, which is the
+// project's limit bounded by the configured upper limit.
+func (s *CrawlerService) CrawlLimit(p models.Project) int {
+	return boundedLimit(p.MaxURLs, s.MaxURLs())
+}
+
+// MaxDuration returns the configured upper limit for the duration of the crawls in minutes.
+func (s *CrawlerService) MaxDuration() int {
+	if s.config.MaxDuration > 0 {
+		return s.config.MaxDuration
+	}
+
+	return DefaultMaxDuration
+}
+
+// MaxSnapshots returns the configured upper limit for the size of the crawl's snapshots in MB.
+func (s *CrawlerService) MaxSnapshots() int {
+	if s.config.MaxSnapshots > 0 {
+		return s.config.MaxSnapshots
+	}
+
+	return DefaultMaxSnapshots
+}
+
+// boundedLimit returns the limit if it is set and lower than the ceiling, otherwise it returns the ceiling.
+func boundedLimit(limit, ceiling int) int {
+	if limit <= 0 || limit > ceiling {
+		return ceiling
+	}
+
+	return limit
+}
+
+// stopReason returns the crawl's stop reason for the error returned by the crawler's StopReason.
+func stopReason(err error) string {
+	switch {
+	case errors.Is(err, crawler.ErrURLLimit):
+		return models.StopReasonURLLimit
+	case errors.Is(err, crawler.ErrMaxDuration):
+		return models.StopReasonMaxDuration
+	case errors.Is(err, crawler.ErrStopped):
+		return models.StopReasonStopped
+	}
+
+	return ""
+}
+
+// GetTLSHosts returns the TLS connection details of the hosts in the crawl.
+func (s *CrawlerService) GetTLSHosts(crawl *models.Crawl) []models.TLSHost {
+	return s.store.FindTLSHosts(crawl.Id)
+}
+
+// tlsHosts returns the crawl's TLS host details from the ones recorded by the crawler.
+func tlsHosts(info []crawler.TLSInfo) []models.TLSHost {
+	hosts := []models.TLSHost{}
+	for _, i := range info {
+		h := models.TLSHost{
+			Host:             i.Host,
+			URL:              i.URL,
+			Protocol:         i.Protocol,
+			HostnameMismatch: i.HostnameMismatch(),
+			ObsoleteVersion:  i.ObsoleteVersion(),
+		}
+
+		if i.Version != 0 {
+			h.TLSVersion = tls.VersionName(i.Version)
+			h.CipherSuite = tls.CipherSuiteName(i.CipherSuite)
+		}
+
+		if i.Error != nil {
+			h.Error = i.Error.Error()
+		}
+
+		if leaf := i.Leaf(); leaf != nil {
+			h.Subject = leaf.Subject.String()
+			h.Issuer = leaf.Issuer.String()
+			h.SANs = leaf.DNSNames
+			for _, ip := range leaf.IPAddresses {
+				h.SANs = append(h.SANs, ip.String())
+			}
+			h.NotAfter = leaf.NotAfter
+		}
+
+		for _, c := range i.Certificates {
+			h.Chain = append(h.Chain, c.Subject.String())
+		}
+
+		hosts = append(hosts, h)
+	}
+
+	return hosts
+}
+
+// IsInterrupted returns true if the project's last crawl was interrupted and can be resumed.
+func (s *CrawlerService) IsInterrupted(p models.Project) bool {
+	return s.store.GetLastCrawl(&p).Interrupted
+}
+
+// IsCrawling returns true if the project has a crawler running.
+func (s *CrawlerService) IsCrawling(p models.Project) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	_, ok := s.crawlers[p.Id]
+
+	return ok
+}
+
 // AddCrawler creates a new project crawler and adds it to the crawlers map. It returns the crawler
 // on success otherwise it returns an error indicating the crawler already exists or there was an
-// error creating it.
-func (s *CrawlerService) addCrawler(u *url.URL, p *models.Project, b *models.BasicAuth) (*crawler.Crawler, error) {
+// error creating it. In list mode the crawler only crawls the URLs added to it.
+func (s *CrawlerService) addCrawler(u *url.URL, p *models.Project, client crawler.Client, listMode bool) (*crawler.Crawler, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
@@ -155,35 +465,155 @@ func (s *CrawlerService) addCrawler(u *url.URL, p *models.Project, b *models.Bas
 		return nil, errors.New("project is already being crawled")
 	}
 
+	urlRules := p.URLRules
+	if p.LoginURL != "" || p.BearerAuth {
+		urlRules = logoutURLRule + "\n" + urlRules
+	}
+
+	rules, err := crawler.ParseURLRules(urlRules)
+	if err != nil {
+		return nil, err
+	}
+
+	startURLs, err := ParseStartURLs(p)
+	if err != nil {
+		return nil, err
+	}
+
 	options := &crawler.Options{
-		CrawlLimit:      CrawlLimit,
-		IgnoreRobotsTxt: p.IgnoreRobotsTxt,
-		FollowNofollow:  p.FollowNofollow,
-		IncludeNoindex:  p.IncludeNoindex,
-		CrawlSitemap:    p.CrawlSitemap,
-		AllowSubdomains: p.AllowSubdomains,
+		CrawlLimit:           s.CrawlLimit(*p),
+		MaxDuration:          time.Duration(boundedLimit(p.MaxDuration, s.MaxDuration())) * time.Minute,
+		IgnoreRobotsTxt:      p.IgnoreRobotsTxt,
+		FollowNofollow:       p.FollowNofollow,
+		IncludeNoindex:       p.IncludeNoindex,
+		CrawlSitemap:         p.CrawlSitemap,
+		AllowSubdomains:      p.AllowSubdomains,
+		Workers:              p.Workers,
+		MinDelay:             time.Duration(p.MinDelay) * time.Millisecond,
+		MaxDelay:             time.Duration(p.MaxDelay) * time.Millisecond,
+		MaxRequestsPerSecond: p.MaxRPS,
+		CrawlDelay:           time.Duration(p.CrawlDelay) * time.Millisecond,
+		URLRules:             rules,
+		Normalizer: crawler.NewNormalizer(crawler.NormalizerOptions{
+			StripParams:   strings.Split(p.StripParams, ","),
+			SortParams:    p.SortParams,
+			DropFragment:  p.DropFragment,
+			LowercaseHost: p.LowercaseHost,
+			TrailingSlash: p.TrailingSlash,
+		}),
+		MaxDepth:         p.MaxDepth,
+		MaxDirectoryURLs: p.MaxDirectoryURLs,
+		ListMode:         listMode,
+		RetryAttempts:    p.RetryAttempts,
+		AdaptiveThrottle: p.AdaptiveThrottle,
+		StartURLs:        startURLs,
+		MaxRedirects:     MaxRedirectHops,
+	}
+
+	if p.UsesRobotsOverride() {
+		options.RobotsOverride = []byte(p.RobotsOverride)
+	}
+
+	if s.config.Storage == DiskStorage {
+		options.Storage, err = crawler.NewDiskStorage(s.config.StorageDir, options.CrawlLimit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Creates a new crawler with the crawler's response handler.
+	s.crawlers[p.Id] = crawler.NewCrawler(u, options, client)
+
+	return s.crawlers[p.Id], nil
+}
+
+// newClient creates the project's HTTP client with its credentials, custom headers and cookies.
+// The client keeps the session cookies in a cookie jar, and if the project uses a login form
+// it logs in before returning the client. The client connects to the project's host overrides
+// instead of the resolved addresses. If the project crawls a local build the client serves the
+// requests from its directory instead.
+func (s *CrawlerService) newClient(u *url.URL, p *models.Project, b *models.BasicAuth) (crawler.Client, error) {
+	if p.LocalPath != "" {
+		return s.newDirClient(u, p)
 	}
 
 	mainDomain := strings.TrimPrefix(u.Host, "www.")
 
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The minimum TLS version is lowered so the hosts using obsolete versions can still be
+	// crawled and reported, and the certificates are verified after the handshake so the
+	// details of the hosts with invalid certificates are recorded.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = crawler.TLSConfig(&tls.Config{MinVersion: tls.VersionTLS10})
+
+	overrides, err := crawler.ParseHostOverrides(p.HostOverrides)
+	if err != nil {
+		return nil, err
+	}
+
+	// The proxy is not used with host overrides as it would resolve the hosts itself.
+	if len(overrides) > 0 {
+		transport.Proxy = nil
+		transport.DialContext = overrides.DialContext(transport.DialContext)
+	}
+
 	httpClient := &http.Client{
-		Timeout: ClientTimeout * time.Second,
+		Jar:       jar,
+		Transport: transport,
+		Timeout:   ClientTimeout * time.Second,
 		CheckRedirect: func(r *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
 	}
 
+	headers, err := crawler.ParseHeaders(p.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	cookies, err := crawler.ParseCookies(p.Cookies)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.BearerAuth && b.Token != "" {
+		headers.Set("Authorization", "Bearer "+b.Token)
+	}
+
+	// The custom headers and cookies are sent only to the domains the crawler is allowed to crawl.
+	headerDomains := []string{mainDomain, "www." + mainDomain}
+	if p.AllowSubdomains {
+		headerDomains = append(headerDomains, "."+mainDomain)
+	}
+
 	client := crawler.NewBasicClient(&crawler.ClientOptions{
 		UserAgent:        s.config.Agent,
 		BasicAuthDomains: []string{mainDomain, "www." + mainDomain},
 		AuthUser:         b.AuthUser,
 		AuthPass:         b.AuthPass,
+		Headers:          headers,
+		Cookies:          cookies,
+		HeaderDomains:    headerDomains,
 	}, httpClient)
 
-	// Creates a new crawler with the crawler's response handler.
-	s.crawlers[p.Id] = crawler.NewCrawler(u, options, client)
+	if p.LoginURL != "" {
+		err := client.FormLogin(&crawler.LoginOptions{
+			URL:       p.LoginURL,
+			UserField: p.LoginUserField,
+			PassField: p.LoginPassField,
+			User:      b.LoginUser,
+			Pass:      b.LoginPass,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	return s.crawlers[p.Id], nil
+	return client, nil
 }
 
 // RemoveCrawler removes a project's crawler from the crawlers map.
@@ -193,3 +623,30 @@ func (s *CrawlerService) removeCrawler(p *models.Project) {
 
 	delete(s.crawlers, p.Id)
 }
+
+// discardCrawler removes a project's crawler that has not been started, releasing its resources.
+func (s *CrawlerService) discardCrawler(p *models.Project, c *crawler.Crawler) {
+	s.removeCrawler(p)
+	c.Discard()
+}
+
+// newDirClient creates a client that serves the requests under the project's URL from its
+// local build directory. It returns an error if the local builds are not enabled in the
+// config or the directory doesn't exist.
+func (s *CrawlerService) newDirClient(u *url.URL, p *models.Project) (crawler.Client, error) {
+	if s.config.LocalDir == "" || !filepath.IsLocal(p.LocalPath) {
+		return nil, errors.New("local builds are not enabled")
+	}
+
+	dir := filepath.Join(s.config.LocalDir, p.LocalPath)
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	return crawler.NewDirClient(dir, u, s.config.Agent), nil
+}
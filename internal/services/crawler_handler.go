@@ -16,6 +16,7 @@ import (
 
 type CrawlerHandlerStorage interface {
 	SavePageReport(*models.PageReport, int64) (*models.PageReport, error)
+	CountSnapshotsSize(int64) int64
 }
 
 type CrawlerHandler struct {
@@ -26,10 +27,6 @@ type CrawlerHandler struct {
 	client              crawler.Client
 }
 
-type crawlerData struct {
-	Depth int
-}
-
 func NewCrawlerHandler(s CrawlerHandlerStorage, b *Broker, r *ReportManager, client crawler.Client) *CrawlerHandler {
 	return &CrawlerHandler{
 		store:               s,
@@ -40,7 +37,14 @@ func NewCrawlerHandler(s CrawlerHandlerStorage, b *Broker, r *ReportManager, cli
 	}
 }
 
-func (s *CrawlerHandler) responseCallback(crawl *models.Crawl, p *models.Project, c *crawler.Crawler) crawler.ResponseCallback {
+// responseCallback returns the crawler's response callback. If the project stores snapshots
+// they are stored until their compressed size reaches maxSnapshots bytes.
+func (s *CrawlerHandler) responseCallback(crawl *models.Crawl, p *models.Project, c *crawler.Crawler, maxSnapshots int64) crawler.ResponseCallback {
+	var snapshotsSize int64
+	if p.StoreSnapshots {
+		snapshotsSize = s.store.CountSnapshotsSize(crawl.Id)
+	}
+
 	return func(r *crawler.ResponseMessage) {
 		pageReport, htmlNode, err := s.buildPageReport(r)
 		if err != nil {
@@ -48,55 +52,27 @@ func (s *CrawlerHandler) responseCallback(crawl *models.Crawl, p *models.Project
 			return
 		}
 
-		// Create a requestData object and increase the Depth value
-		// according to the data in the responseMessage's Data.
-		// If there's no crawlerData in the responseMessage the Depth value
-		// is -1 meaning it is undefined.
-		requestData := crawlerData{Depth: -1}
-		d, ok := r.Data.(crawlerData)
-		if ok {
-			requestData.Depth = d.Depth + 1
+		if r.OriginalURL != nil {
+			pageReport.OriginalURL = r.OriginalURL.String()
 		}
 
+		s.normalizeURLs(pageReport, c)
+
+		pageReport.RedirectChain = s.redirectChain(r, c)
+
 		pageReport.TTFB = r.TTFB
-		pageReport.Depth = d.Depth
+		pageReport.Attempts = r.Attempts
+		// The URLs with an unknown depth, such as the sitemap and listed URLs, are reported
+		// at depth zero so the reports don't have a negative depth.
+		pageReport.Depth = max(r.Depth, 0)
 		pageReport.BlockedByRobotstxt = r.Blocked
 		pageReport.InSitemap = r.InSitemap
 		pageReport.Crawled = !pageReport.Timeout && (p.FollowNofollow || !pageReport.Nofollow)
 
-		// Add link URLs to the crawler considering the nofollow attribute as well as
-		// the projects FollowNoFollow option. In case the URL is blocked by the robots.txt
-		// file a new blocked PageReport is saved. Both internal and external links
-		// are added as the crawler will discard the domains that are not allowed.
-		links := append(pageReport.Links, pageReport.ExternalLinks...)
-		for _, l := range links {
-			if !l.NoFollow || p.FollowNofollow {
-				err := c.AddRequest(&crawler.RequestMessage{URL: l.ParsedURL, Data: requestData})
-				if errors.Is(err, crawler.ErrBlockedByRobotstxt) {
-					s.saveBlockedPageReport(l.ParsedURL, crawl)
-					crawl.BlockedByRobotstxt++
-				}
-			}
-		}
-
-		// Add the indirect URLs such as canonicals, redirects or hreflang URLs to the crawler.
-		// In of the URL being blocked by the robots.txt save a new blocked PageReport.
-		for _, u := range s.getInderictURLs(pageReport) {
-			err := c.AddRequest(&crawler.RequestMessage{URL: u, Data: requestData})
-			if errors.Is(err, crawler.ErrBlockedByRobotstxt) {
-				s.saveBlockedPageReport(u, crawl)
-				crawl.BlockedByRobotstxt++
-			}
-		}
-
-		// Add the resource URLs to the crawler. If the URL is blocked in the robots.txt
-		// Save a new blocked PageReport.
-		for _, u := range s.getResourceURLs(pageReport) {
-			err := c.AddRequest(&crawler.RequestMessage{URL: u, IgnoreDomain: true, Data: requestData})
-			if errors.Is(err, crawler.ErrBlockedByRobotstxt) {
-				s.saveBlockedPageReport(u, crawl)
-				crawl.BlockedByRobotstxt++
-			}
+		// In list mode only the listed URLs are crawled, so the URLs found
+		// in the response are not added to the crawler.
+		if !crawl.ListMode {
+			s.addDiscoveredURLs(pageReport, r, crawl, p, c)
 		}
 
 		// Check the external links if the project is set to do so.
@@ -109,6 +85,11 @@ func (s *CrawlerHandler) responseCallback(crawl *models.Crawl, p *models.Project
 		// If the pageReport is saved correctly create the page issues, otherwise
 		// log the error.
 		if !pageReport.Noindex || p.IncludeNoindex {
+			if p.StoreSnapshots && len(pageReport.Body) > 0 && snapshotsSize < maxSnapshots {
+				pageReport.Snapshot = s.snapshot(pageReport, maxSnapshots-snapshotsSize)
+				snapshotsSize += int64(len(pageReport.Snapshot))
+			}
+
 			pageReport, err = s.store.SavePageReport(pageReport, crawl.Id)
 			if err == nil {
 				headers := make(http.Header)
@@ -134,6 +115,22 @@ func (s *CrawlerHandler) responseCallback(crawl *models.Crawl, p *models.Project
 	}
 }
 
+// snapshot returns the page report's compressed body, or nil if it is bigger than the
+// available space.
+func (s *CrawlerHandler) snapshot(pageReport *models.PageReport, available int64) []byte {
+	snapshot, err := compressSnapshot(pageReport.Body)
+	if err != nil {
+		log.Printf("compressSnapshot: %s %v\n", pageReport.URL, err)
+		return nil
+	}
+
+	if int64(len(snapshot)) > available {
+		return nil
+	}
+
+	return snapshot
+}
+
 // buildPageReport builds a PageReport based on the responseMessage checking for Timeout errors.
 func (s *CrawlerHandler) buildPageReport(r *crawler.ResponseMessage) (*models.PageReport, *html.Node, error) {
 	// Check if the response caused an error and save a pageReport.
@@ -176,6 +173,105 @@ func (s *CrawlerHandler) buildPageReport(r *crawler.ResponseMessage) (*models.Pa
 	return pageReport, htmlNode, nil
 }
 
+// addDiscoveredURLs adds the links, indirect URLs and resources found in the pageReport to
+// the crawler, counting or saving the URLs that can't be added.
+func (s *CrawlerHandler) addDiscoveredURLs(pageReport *models.PageReport, r *crawler.ResponseMessage, crawl *models.Crawl, p *models.Project, c *crawler.Crawler) {
+	// Increase the depth for the URLs found in the response. If the response's
+	// depth is unknown, as it happens with the sitemap URLs, the Depth value
+	// is -1 meaning it is undefined.
+	depth := -1
+	if r.Depth >= 0 {
+		depth = r.Depth + 1
+	}
+
+	// Add link URLs to the crawler considering the nofollow attribute as well as
+	// the projects FollowNoFollow option. In case the URL is blocked by the robots.txt
+	// file a new blocked PageReport is saved. URLs excluded by the project's URL rules
+	// are only counted. Both internal and external links are added as the crawler
+	// will discard the domains that are not allowed.
+	links := append(pageReport.Links, pageReport.ExternalLinks...)
+	for _, l := range links {
+		if !l.NoFollow || p.FollowNofollow {
+			err := c.AddRequest(&crawler.RequestMessage{URL: l.ParsedURL, Depth: depth})
+			s.handleAddRequestError(err, l.ParsedURL, crawl)
+		}
+	}
+
+	// Add the indirect URLs such as canonicals, redirects or hreflang URLs to the crawler.
+	// In of the URL being blocked by the robots.txt save a new blocked PageReport.
+	for _, u := range s.getInderictURLs(pageReport) {
+		err := c.AddRequest(&crawler.RequestMessage{URL: u, Depth: depth})
+		s.handleAddRequestError(err, u, crawl)
+	}
+
+	// Add the resource URLs to the crawler. If the URL is blocked in the robots.txt
+	// Save a new blocked PageReport.
+	for _, u := range s.getResourceURLs(pageReport) {
+		err := c.AddRequest(&crawler.RequestMessage{URL: u, IgnoreDomain: true, Depth: depth})
+		s.handleAddRequestError(err, u, crawl)
+	}
+}
+
+// normalizeURLs normalizes the pageReport's internal links and redirect URL with the crawler's
+// normalizer, so they match the URLs of the pageReports that will be stored for them.
+func (s *CrawlerHandler) normalizeURLs(pageReport *models.PageReport, c *crawler.Crawler) {
+	for n, l := range pageReport.Links {
+		if l.ParsedURL == nil {
+			continue
+		}
+
+		pageReport.Links[n].ParsedURL = c.Normalize(l.ParsedURL)
+		pageReport.Links[n].URL = pageReport.Links[n].ParsedURL.String()
+	}
+
+	if pageReport.RedirectURL == "" {
+		return
+	}
+
+	if u, err := url.Parse(pageReport.RedirectURL); err == nil {
+		pageReport.RedirectURL = c.Normalize(u).String()
+	}
+}
+
+// redirectChain returns the response's redirect chain, flagging the hops to URLs in domains
+// the crawler is not allowed to crawl.
+func (s *CrawlerHandler) redirectChain(r *crawler.ResponseMessage, c *crawler.Crawler) []models.RedirectHop {
+	var chain []models.RedirectHop
+	for _, h := range r.Redirects {
+		hop := models.RedirectHop{
+			URL:        h.URL,
+			StatusCode: h.StatusCode,
+			Location:   h.Location,
+			Blocked:    h.Blocked,
+		}
+
+		if u, err := url.Parse(h.URL); err == nil {
+			hop.External = !c.DomainIsAllowed(u.Host)
+		}
+
+		chain = append(chain, hop)
+	}
+
+	return chain
+}
+
+// handleAddRequestError updates the crawl with the result of adding a URL to the crawler.
+// If the URL is blocked by the robots.txt a new blocked PageReport is saved, and if it is
+// excluded by the project's URL rules or limits it is counted in the crawl.
+func (s *CrawlerHandler) handleAddRequestError(err error, u *url.URL, crawl *models.Crawl) {
+	switch {
+	case errors.Is(err, crawler.ErrBlockedByRobotstxt):
+		s.saveBlockedPageReport(u, crawl)
+		crawl.BlockedByRobotstxt++
+	case errors.Is(err, crawler.ErrExcludedByRule):
+		crawl.ExcludedByRule++
+	case errors.Is(err, crawler.ErrMaxDepth):
+		crawl.ExceededMaxDepth++
+	case errors.Is(err, crawler.ErrDirectoryLimit):
+		crawl.ExceededDirectoryLimit++
+	}
+}
+
 // saveBlockedPageReport saves a new PageReport with the specified URL and Crawl,
 // setting the blockedByRobotstxt field to true.
 func (s *CrawlerHandler) saveBlockedPageReport(u *url.URL, crawl *models.Crawl) {
@@ -0,0 +1,115 @@
+package services_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+	"github.com/stjudewashere/seonaut/internal/services"
+)
+
+// crawlTLS crawls the TLS test server at the start URL and returns the page reports along
+// with the TLS hosts recorded during the crawl.
+func crawlTLS(t *testing.T, ts *httptest.Server, start string) ([]string, []crawler.TLSInfo) {
+	transport := ts.Client().Transport.(*http.Transport).Clone()
+	transport.TLSClientConfig = crawler.TLSConfig(transport.TLSClientConfig)
+
+	client := crawler.NewBasicClient(&crawler.ClientOptions{UserAgent: "TEST_UA"}, &http.Client{
+		Transport: transport,
+		CheckRedirect: func(r *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	})
+
+	u, err := url.Parse(start)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := crawler.NewCrawler(u, &crawler.Options{CrawlLimit: 10, Workers: 1}, client)
+
+	hosts := []string{}
+	c.OnResponse(func(r *crawler.ResponseMessage) {
+		if r.Error != nil {
+			hosts = append(hosts, r.URL.Host)
+			return
+		}
+
+		pageReport, _, err := services.NewFromHTTPResponse(r.Response)
+		if err != nil {
+			t.Errorf("NewFromHTTPResponse: %v", err)
+			return
+		}
+
+		hosts = append(hosts, pageReport.ParsedURL.Host)
+	})
+
+	for _, p := range []string{"/", "/a"} {
+		c.AddRequest(&crawler.RequestMessage{URL: u.JoinPath(p)})
+	}
+
+	c.Start()
+
+	return hosts, c.TLSHosts()
+}
+
+// TestCrawlTLSHosts tests the TLS hosts recorded in a crawl have the same host as the crawled
+// pages, which is what the TLS issue reporters join them on, even though the first connection
+// to the host is opened by the robots.txt request.
+func TestCrawlTLSHosts(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+	}))
+	defer ts.Close()
+
+	pageHosts, tlsHosts := crawlTLS(t, ts, ts.URL)
+	if len(pageHosts) != 2 || len(tlsHosts) != 1 {
+		t.Fatalf("expected 2 pages and 1 TLS host, got %v and %d TLS hosts", pageHosts, len(tlsHosts))
+	}
+
+	if !strings.HasSuffix(tlsHosts[0].URL, "/robots.txt") {
+		t.Errorf("expected the connection to be opened by the robots.txt request, got %s", tlsHosts[0].URL)
+	}
+
+	for _, h := range pageHosts {
+		if h != tlsHosts[0].Host {
+			t.Errorf("page host %s != TLS host %s", h, tlsHosts[0].Host)
+		}
+	}
+}
+
+// TestCrawlTLSHostsVerificationError tests the handshake details of a host with a certificate
+// that is not valid for its name are recorded, while the requests to the host still fail.
+func TestCrawlTLSHostsVerificationError(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	// The test certificate is valid for 127.0.0.1 and example.com but not for localhost.
+	start := strings.Replace(ts.URL, "127.0.0.1", "localhost", 1)
+
+	pageHosts, tlsHosts := crawlTLS(t, ts, start)
+	if len(pageHosts) != 2 || len(tlsHosts) != 1 {
+		t.Fatalf("expected 2 pages and 1 TLS host, got %v and %d TLS hosts", pageHosts, len(tlsHosts))
+	}
+
+	h := tlsHosts[0]
+	if h.Error == nil {
+		t.Error("Error nil != certificate error")
+	}
+
+	if h.Version != tls.VersionTLS13 {
+		t.Errorf("Version %s != TLS 1.3", tls.VersionName(h.Version))
+	}
+
+	if !h.HostnameMismatch() {
+		t.Error("HostnameMismatch false != true")
+	}
+
+	if pageHosts[0] != h.Host {
+		t.Errorf("page host %s != TLS host %s", pageHosts[0], h.Host)
+	}
+}
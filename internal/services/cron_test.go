@@ -0,0 +1,64 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/services"
+)
+
+func TestCronNext(t *testing.T) {
+	// Wednesday, January 10 2024.
+	from := time.Date(2024, time.January, 10, 10, 30, 0, 0, time.UTC)
+
+	table := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.January, 10, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.January, 10, 10, 45, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2024, time.January, 11, 3, 0, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2024, time.January, 11, 10, 30, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2024, time.January, 11, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2024, time.January, 14, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, time.January, 14, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 13 * 5", time.Date(2024, time.January, 12, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, tc := range table {
+		c, err := services.ParseCron(tc.expr)
+		if err != nil {
+			t.Errorf("ParseCron %q: %v", tc.expr, err)
+			continue
+		}
+
+		if got := c.Next(from); !got.Equal(tc.want) {
+			t.Errorf("%q: want %v, got %v", tc.expr, tc.want, got)
+		}
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	table := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+	}
+
+	for _, expr := range table {
+		if _, err := services.ParseCron(expr); err == nil {
+			t.Errorf("ParseCron %q: expected error", expr)
+		}
+	}
+}
@@ -16,6 +16,7 @@ type (
 		CountByCanonical(int64) int
 		CountImagesAlt(int64) *models.AltCount
 		CountScheme(int64) *models.SchemeCount
+		CountCompression(int64) *models.CompressionCount
 		CountByNonCanonical(int64) int
 		GetStatusCodeByDepth(crawlId int64) []models.StatusCodeByDepth
 	}
@@ -51,6 +52,11 @@ func (s *DashboardService) GetSchemeCount(crawlId int64) *models.SchemeCount {
 	return s.store.CountScheme(crawlId)
 }
 
+// Returns the count of compressed and uncompressed text responses and their sizes.
+func (s *DashboardService) GetCompressionCount(crawlId int64) *models.CompressionCount {
+	return s.store.CountCompression(crawlId)
+}
+
 // Returns a count of PageReports that are canonical or not.
 func (s *DashboardService) GetCanonicalCount(crawlId int64) *models.CanonicalCount {
 	return &models.CanonicalCount{
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+var ErrNoSecretKey = errors.New("secret key is not configured")
+
+// Encrypter encrypts and decrypts the project secrets, such as custom headers and cookies,
+// before they are stored in the database. It uses AES-GCM with a key derived from the
+// secret key in the crawler's configuration.
+type Encrypter struct {
+	aead cipher.AEAD
+}
+
+// NewEncrypter returns a new Encrypter using the specified secret key.
+// It returns nil if the key is empty, in which case secrets can't be encrypted.
+func NewEncrypter(key string) *Encrypter {
+	if key == "" {
+		return nil
+	}
+
+	k := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(k[:])
+	if err != nil {
+		return nil
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil
+	}
+
+	return &Encrypter{aead: aead}
+}
+
+// Encrypt returns the encrypted and base64 encoded value of s.
+// Empty strings are not encrypted.
+func (e *Encrypter) Encrypt(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+
+	if e == nil {
+		return "", ErrNoSecretKey
+	}
+
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := e.aead.Seal(nonce, nonce, []byte(s), nil)
+
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the decrypted value of a string encrypted with Encrypt.
+func (e *Encrypter) Decrypt(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+
+	if e == nil {
+		return "", ErrNoSecretKey
+	}
+
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", err
+	}
+
+	if len(data) < e.aead.NonceSize() {
+		return "", errors.New("encrypted value is too short")
+	}
+
+	nonce, sealed := data[:e.aead.NonceSize()], data[e.aead.NonceSize():]
+	plain, err := e.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plain), nil
+}
@@ -0,0 +1,47 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/services"
+)
+
+func TestEncrypter(t *testing.T) {
+	e := services.NewEncrypter("test key")
+	secret := "X-Bypass-Cdn: secret"
+
+	encrypted, err := e.Encrypt(secret)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if encrypted == secret {
+		t.Error("encrypted value should not be the same as the secret")
+	}
+
+	decrypted, err := e.Decrypt(encrypted)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if decrypted != secret {
+		t.Errorf("decrypted value %q should be %q", decrypted, secret)
+	}
+
+	if _, err := services.NewEncrypter("other key").Decrypt(encrypted); err == nil {
+		t.Error("decrypting with a different key should return an error")
+	}
+}
+
+func TestEncrypterWithoutKey(t *testing.T) {
+	e := services.NewEncrypter("")
+
+	if _, err := e.Encrypt("secret"); !errors.Is(err, services.ErrNoSecretKey) {
+		t.Errorf("expected ErrNoSecretKey, got %v", err)
+	}
+
+	if v, err := e.Encrypt(""); v != "" || err != nil {
+		t.Errorf("empty values should not be encrypted, got %q %v", v, err)
+	}
+}
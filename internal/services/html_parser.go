@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -11,9 +12,13 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
+	"unicode"
 
+	"github.com/stjudewashere/seonaut/internal/crawler"
 	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/simhash"
 
 	"golang.org/x/net/html"
 )
@@ -22,9 +27,21 @@ const (
 	// MaxBodySize is the limit of the retrieved response body in bytes.
 	// The default value for MaxBodySize is 10MB (10 * 1024 * 1024 bytes).
 	maxBodySize = 10 * 1024 * 1024
+
+	// Max number of response headers stored for each page report.
+	maxResponseHeaders = 100
 )
 
+// transferSizer is implemented by the response bodies that count the bytes transferred
+// over the network, such as the crawler's TransferBody.
+type transferSizer interface {
+	TransferSize() int64
+}
+
 // Create a new PageReport from an http.Response.
+// The transfer size is the number of bytes received if the body counts them, otherwise
+// it is taken from the Content-Length header of compressed responses or the body's size.
+// If the body's content encoding can't be decoded the page report is created without it.
 func NewFromHTTPResponse(r *http.Response) (*models.PageReport, *html.Node, error) {
 	defer r.Body.Close()
 
@@ -32,11 +49,54 @@ func NewFromHTTPResponse(r *http.Response) (*models.PageReport, *html.Node, erro
 	bodyReader = io.LimitReader(bodyReader, int64(maxBodySize))
 
 	b, err := io.ReadAll(bodyReader)
-	if err != nil {
+	undecoded := errors.Is(err, crawler.ErrUnsupportedEncoding)
+	if err != nil && !undecoded {
 		return &models.PageReport{}, &html.Node{}, err
 	}
 
-	return NewHTMLParser(r.Request.URL, r.StatusCode, &r.Header, b, r.ContentLength)
+	if undecoded {
+		b = nil
+	}
+
+	pageReport, htmlNode, err := NewHTMLParser(r.Request.URL, r.StatusCode, &r.Header, b, r.ContentLength)
+	if err != nil {
+		return pageReport, htmlNode, err
+	}
+
+	pageReport.Body = b
+	pageReport.UndecodedBody = undecoded
+	pageReport.ContentEncoding = r.Header.Get("Content-Encoding")
+	pageReport.TransferSize = pageReport.Size
+	if t, ok := r.Body.(transferSizer); ok && t.TransferSize() > 0 {
+		pageReport.TransferSize = t.TransferSize()
+	} else if pageReport.ContentEncoding != "" && r.ContentLength > 0 {
+		pageReport.TransferSize = r.ContentLength
+	}
+
+	return pageReport, htmlNode, nil
+}
+
+// responseHeaders returns the response headers sorted by name, with one entry for each
+// value of the headers that are sent more than once.
+func responseHeaders(headers *http.Header) []models.ResponseHeader {
+	names := make([]string, 0, len(*headers))
+	for name := range *headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rh := []models.ResponseHeader{}
+	for _, name := range names {
+		for _, v := range (*headers)[name] {
+			if len(rh) == maxResponseHeaders {
+				return rh
+			}
+
+			rh = append(rh, models.ResponseHeader{Name: name, Value: v})
+		}
+	}
+
+	return rh
 }
 
 // Return a new PageReport.
@@ -58,6 +118,7 @@ func NewHTMLParser(u *url.URL, status int, headers *http.Header, body []byte, co
 		StatusCode:  status,
 		ContentType: headers.Get("Content-Type"),
 		Size:        size,
+		Headers:     responseHeaders(headers),
 	}
 
 	pageReport.MediaType, _, err = mime.ParseMediaType(pageReport.ContentType)
@@ -106,6 +167,7 @@ func NewHTMLParser(u *url.URL, status int, headers *http.Header, body []byte, co
 		bnode := parser.htmlBodyNode()
 		if bnode != nil {
 			pageReport.Words = countWords(bnode)
+			pageReport.SimHash = simhash.Fingerprint(visibleWords(bnode))
 		}
 
 		pageReport.BodyHash, err = hashString(body)
@@ -161,6 +223,49 @@ func countWords(n *html.Node) int {
 	return len(strings.Fields(t))
 }
 
+// visibleWords returns the lowercased words of the text in an HTML node. The text in
+// scripts, styles and in the navigation, header, footer and aside elements is skipped,
+// as it is usually repeated across the site's pages.
+func visibleWords(n *html.Node) []string {
+	skip := map[string]bool{
+		"script":   true,
+		"style":    true,
+		"noscript": true,
+		"template": true,
+		"svg":      true,
+		"nav":      true,
+		"header":   true,
+		"footer":   true,
+		"aside":    true,
+	}
+
+	var buf bytes.Buffer
+	var output func(*html.Node)
+	output = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			buf.WriteString(n.Data)
+			buf.WriteString(" ")
+			return
+		case html.ElementNode:
+			if skip[n.Data] {
+				return
+			}
+		case html.CommentNode:
+			return
+		}
+
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			output(child)
+		}
+	}
+	output(n)
+
+	return strings.FieldsFunc(strings.ToLower(buf.String()), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
 // Hash a string using sha256 and returns is hex representation as a string.
 func hashString(input []byte) (string, error) {
 	hasher := sha256.New()
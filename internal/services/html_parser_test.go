@@ -1,13 +1,19 @@
 package services_test
 
 import (
+	"bytes"
+	"compress/gzip"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/stjudewashere/seonaut/internal/crawler"
+	"github.com/stjudewashere/seonaut/internal/models"
 	"github.com/stjudewashere/seonaut/internal/services"
 )
 
@@ -438,3 +444,142 @@ func TestRobotsNone(t *testing.T) {
 		t.Error("NewPageReport Nofollow should be true")
 	}
 }
+
+func TestPageReportTransferSize(t *testing.T) {
+	u, err := url.Parse(testURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	w.Write([]byte(strings.Repeat("<p>content</p>", 100)))
+	w.Close()
+
+	transferSize := int64(compressed.Len())
+	r := &http.Response{
+		StatusCode: 200,
+		Header: http.Header{
+			"Content-Type":     []string{"text/html"},
+			"Content-Encoding": []string{"gzip"},
+		},
+		Body:    crawler.NewTransferBody(io.NopCloser(&compressed), "gzip"),
+		Request: &http.Request{URL: u},
+	}
+
+	pageReport, _, err := services.NewFromHTTPResponse(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pageReport.ContentEncoding != "gzip" {
+		t.Errorf("ContentEncoding %s != gzip", pageReport.ContentEncoding)
+	}
+
+	if pageReport.Size != 1400 {
+		t.Errorf("Size %d != 1400", pageReport.Size)
+	}
+
+	if pageReport.TransferSize != transferSize {
+		t.Errorf("TransferSize %d != %d", pageReport.TransferSize, transferSize)
+	}
+}
+
+// TestPageReportUnsupportedEncoding tests the page report is created without the body
+// if its content encoding can't be decoded.
+func TestPageReportUnsupportedEncoding(t *testing.T) {
+	u, err := url.Parse(testURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &http.Response{
+		StatusCode: 200,
+		Header: http.Header{
+			"Content-Type":     []string{"text/html"},
+			"Content-Encoding": []string{"zstd"},
+		},
+		Body:    crawler.NewTransferBody(io.NopCloser(strings.NewReader("\x28\xb5\x2f\xfd")), "zstd"),
+		Request: &http.Request{URL: u},
+	}
+
+	pageReport, _, err := services.NewFromHTTPResponse(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !pageReport.UndecodedBody {
+		t.Error("UndecodedBody should be true")
+	}
+
+	if len(pageReport.Body) != 0 {
+		t.Errorf("Body should be empty, got %q", pageReport.Body)
+	}
+}
+
+func TestResponseHeaders(t *testing.T) {
+	u, err := url.Parse(testURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := []byte("<html>")
+	headers := http.Header{
+		"Server":       []string{"Apache"},
+		"Content-Type": []string{"text/html"},
+		"Set-Cookie":   []string{"a=1", "b=2"},
+	}
+
+	pageReport, _, err := services.NewHTMLParser(u, 200, &headers, body, int64(len(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []models.ResponseHeader{
+		{Name: "Content-Type", Value: "text/html"},
+		{Name: "Server", Value: "Apache"},
+		{Name: "Set-Cookie", Value: "a=1"},
+		{Name: "Set-Cookie", Value: "b=2"},
+	}
+
+	if len(pageReport.Headers) != len(expected) {
+		t.Fatalf("Headers: %d != %d", len(pageReport.Headers), len(expected))
+	}
+
+	for i, h := range expected {
+		if pageReport.Headers[i] != h {
+			t.Errorf("Headers[%d]: %v != %v", i, pageReport.Headers[i], h)
+		}
+	}
+}
+
+func TestSimHash(t *testing.T) {
+	u, err := url.Parse(testURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headers := http.Header{
+		"Content-Type": []string{"text/html"},
+	}
+
+	parse := func(body string) uint64 {
+		pageReport, _, err := services.NewHTMLParser(u, 200, &headers, []byte(body), int64(len(body)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return pageReport.SimHash
+	}
+
+	// The text in the navigation and the scripts is not part of the fingerprint.
+	a := parse(`<html><body><nav>Home About</nav><p>Near duplicate pages have the same text.</p></body></html>`)
+	b := parse(`<html><body><nav>Blog Contact</nav><script>var a = 1;</script><p>Near <b>duplicate</b> pages have the same text!</p></body></html>`)
+	if a == 0 || a != b {
+		t.Errorf("SimHash %x != %x", a, b)
+	}
+
+	if h := parse(`<html><body><script>var a = 1;</script></body></html>`); h != 0 {
+		t.Errorf("SimHash without text %x != 0", h)
+	}
+}
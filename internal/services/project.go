@@ -2,11 +2,28 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"log"
 	"net/url"
+	"path/filepath"
+	"strings"
 
+	"github.com/stjudewashere/seonaut/internal/crawler"
 	"github.com/stjudewashere/seonaut/internal/models"
 )
 
+const (
+	// Default crawl politeness settings for new projects.
+	DefaultWorkers       = 2
+	DefaultMaxDelay      = 1500
+	DefaultRetryAttempts = 3
+
+	// Upper limits for the crawl politeness settings.
+	MaxWorkers       = 10
+	MaxDelay         = 60000
+	MaxRetryAttempts = 10
+)
+
 type (
 	ProjectServiceStorage interface {
 		SaveProject(*models.Project, int)
@@ -19,12 +36,13 @@ type (
 	}
 
 	ProjectService struct {
-		storage ProjectServiceStorage
+		storage   ProjectServiceStorage
+		encrypter *Encrypter
 	}
 )
 
-func NewProjectService(s ProjectServiceStorage) *ProjectService {
-	return &ProjectService{storage: s}
+func NewProjectService(s ProjectServiceStorage, e *Encrypter) *ProjectService {
+	return &ProjectService{storage: s, encrypter: e}
 }
 
 // SaveProject stores a new project.
@@ -38,19 +56,38 @@ func (s *ProjectService) SaveProject(project *models.Project, userId int) error
 		return errors.New("protocol not supported")
 	}
 
-	s.storage.SaveProject(project, userId)
+	if project.Workers == 0 {
+		project.Workers = DefaultWorkers
+		project.MaxDelay = DefaultMaxDelay
+		project.RetryAttempts = DefaultRetryAttempts
+	}
+
+	if err := validateCrawlSettings(project); err != nil {
+		return err
+	}
+
+	encrypted, err := s.encryptSecrets(project)
+	if err != nil {
+		return err
+	}
+
+	s.storage.SaveProject(encrypted, userId)
 
 	return nil
 }
 
 // Return a project specified by id and user.
-// It populates the Host field from the project's URL.
+// It populates the Host field from the project's URL and decrypts the project's secrets.
+// The secrets that can't be decrypted are cleared, so the project can still be used and
+// the secrets can be entered again.
 func (s *ProjectService) FindProject(id, uid int) (models.Project, error) {
 	project, err := s.storage.FindProjectById(id, uid)
 	if err != nil {
 		return project, err
 	}
 
+	s.decryptSecrets(&project)
+
 	parsedURL, err := url.Parse(project.URL)
 	if err != nil {
 		return project, err
@@ -72,5 +109,153 @@ func (s *ProjectService) DeleteProject(p *models.Project) {
 
 // Update project details.
 func (s *ProjectService) UpdateProject(p *models.Project) error {
-	return s.storage.UpdateProject(p)
+	if err := validateCrawlSettings(p); err != nil {
+		return err
+	}
+
+	encrypted, err := s.encryptSecrets(p)
+	if err != nil {
+		return err
+	}
+
+	return s.storage.UpdateProject(encrypted)
+}
+
+// encryptSecrets returns a copy of the project with its custom headers and cookies encrypted
+// so they can be stored. It returns an error if there are secrets and no secret key is set.
+func (s *ProjectService) encryptSecrets(p *models.Project) (*models.Project, error) {
+	encrypted := *p
+
+	var err error
+	if encrypted.Headers, err = s.encrypter.Encrypt(p.Headers); err != nil {
+		return nil, err
+	}
+
+	if encrypted.Cookies, err = s.encrypter.Encrypt(p.Cookies); err != nil {
+		return nil, err
+	}
+
+	return &encrypted, nil
+}
+
+// decryptSecrets decrypts the project's custom headers and cookies. The ones that can't be
+// decrypted, for instance if the secret key has changed or is missing, are cleared.
+func (s *ProjectService) decryptSecrets(p *models.Project) {
+	var err error
+	if p.Headers, err = s.encrypter.Decrypt(p.Headers); err != nil {
+		log.Printf("decryptSecrets: project %d headers %v\n", p.Id, err)
+		p.Headers = ""
+	}
+
+	if p.Cookies, err = s.encrypter.Decrypt(p.Cookies); err != nil {
+		log.Printf("decryptSecrets: project %d cookies %v\n", p.Id, err)
+		p.Cookies = ""
+	}
+}
+
+// ParseStartURLs returns the project's additional start URLs. It returns an error if any
+// of them is not a valid http or https URL in the project's domain, or in one of its
+// subdomains if the project allows them.
+func ParseStartURLs(p *models.Project) ([]*url.URL, error) {
+	project, err := url.Parse(p.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	mainDomain := strings.TrimPrefix(project.Host, "www.")
+
+	urls := []*url.URL{}
+	for _, s := range p.StartURLList() {
+		u, err := url.Parse(s)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("start URL %q is not valid", s)
+		}
+
+		allowed := u.Host == mainDomain || u.Host == "www."+mainDomain ||
+			(p.AllowSubdomains && strings.HasSuffix(u.Host, "."+mainDomain))
+		if !allowed {
+			return nil, fmt.Errorf("start URL %q is not in the project's domain", s)
+		}
+
+		if u.Path == "" {
+			u.Path = "/"
+		}
+
+		urls = append(urls, u)
+	}
+
+	return urls, nil
+}
+
+// validateCrawlSettings checks the project's concurrency, politeness and limit settings are
+// within bounds and the URL rules are valid.
+func validateCrawlSettings(p *models.Project) error {
+	if p.Workers < 1 || p.Workers > MaxWorkers {
+		return errors.New("number of workers out of range")
+	}
+
+	if p.MinDelay < 0 || p.MaxDelay < 0 || p.MinDelay > MaxDelay || p.MaxDelay > MaxDelay {
+		return errors.New("delay out of range")
+	}
+
+	if p.CrawlDelay < 0 || p.CrawlDelay > MaxDelay {
+		return errors.New("crawl delay out of range")
+	}
+
+	if p.MinDelay > p.MaxDelay {
+		return errors.New("min delay is greater than max delay")
+	}
+
+	if p.MaxRPS < 0 {
+		return errors.New("max requests per second can not be negative")
+	}
+
+	if p.RetryAttempts < 0 || p.RetryAttempts > MaxRetryAttempts {
+		return errors.New("retry attempts out of range")
+	}
+
+	if p.MaxDepth < 0 || p.MaxDirectoryURLs < 0 || p.MaxURLs < 0 || p.MaxDuration < 0 {
+		return errors.New("crawl limits can not be negative")
+	}
+
+	if p.TrailingSlash != "" && p.TrailingSlash != crawler.TrailingSlashAdd && p.TrailingSlash != crawler.TrailingSlashRemove {
+		return errors.New("trailing slash option is not valid")
+	}
+
+	if _, err := crawler.ParseURLRules(p.URLRules); err != nil {
+		return err
+	}
+
+	if _, err := ParseStartURLs(p); err != nil {
+		return err
+	}
+
+	if p.LoginURL != "" {
+		u, err := url.Parse(p.LoginURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return errors.New("login URL is not valid")
+		}
+
+		if p.LoginUserField == "" || p.LoginPassField == "" {
+			return errors.New("login form fields are required")
+		}
+	}
+
+	if _, err := crawler.ParseHeaders(p.Headers); err != nil {
+		return err
+	}
+
+	if _, err := crawler.ParseCookies(p.Cookies); err != nil {
+		return err
+	}
+
+	if _, err := crawler.ParseHostOverrides(p.HostOverrides); err != nil {
+		return err
+	}
+
+	if p.LocalPath != "" && !filepath.IsLocal(p.LocalPath) {
+		return errors.New("local build path must be relative to the local directory")
+	}
+
+	return nil
 }
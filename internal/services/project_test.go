@@ -14,6 +14,8 @@ const (
 	projectURL = "https://example.com"
 	urlHost    = "example.com"
 	urlScheme  = "https"
+
+	secretsId = 2 // Id of the project whose secrets can't be decrypted.
 )
 
 type storage struct{}
@@ -27,6 +29,16 @@ func (s *storage) UpdateProject(p *models.Project) error {
 func (s *storage) FindProjectById(id, uid int) (models.Project, error) {
 	p := models.Project{}
 
+	if id == secretsId && uid == guid {
+		encrypted, _ := services.NewEncrypter("rotated key").Encrypt("consent=yes")
+		p.Id = int64(id)
+		p.URL = projectURL
+		p.Headers = "not encrypted"
+		p.Cookies = encrypted
+
+		return p, nil
+	}
+
 	if id != gid || uid != guid {
 		return p, errors.New("Project does not exist")
 	}
@@ -37,7 +49,7 @@ func (s *storage) FindProjectById(id, uid int) (models.Project, error) {
 }
 func (s *storage) DeleteProjectCrawls(*models.Project) {}
 
-var service = services.NewProjectService(&storage{})
+var service = services.NewProjectService(&storage{}, services.NewEncrypter("test key"))
 
 func TestFindProjectById(t *testing.T) {
 	p, err := service.FindProject(gid, guid)
@@ -59,6 +71,23 @@ func TestFindProjectById(t *testing.T) {
 	}
 }
 
+// TestFindProjectUndecryptableSecrets tests the project is returned without the secrets
+// that can't be decrypted.
+func TestFindProjectUndecryptableSecrets(t *testing.T) {
+	p, err := service.FindProject(secretsId, guid)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if p.Headers != "" || p.Cookies != "" {
+		t.Errorf("expected the secrets to be cleared, got headers %q and cookies %q", p.Headers, p.Cookies)
+	}
+
+	if p.Host != urlHost {
+		t.Errorf("p.Host: %s != %s", p.Host, urlHost)
+	}
+}
+
 func TestSaveProject(t *testing.T) {
 	// Valid URL
 	err := service.SaveProject(&models.Project{URL: projectURL}, guid)
@@ -78,3 +107,87 @@ func TestSaveProject(t *testing.T) {
 		t.Error("TestSaveProject: not supported scheme should return error")
 	}
 }
+
+func TestUpdateProjectCrawlSettings(t *testing.T) {
+	p := &models.Project{URL: projectURL, Workers: 4, MinDelay: 100, MaxDelay: 500, MaxRPS: 2}
+	if err := service.UpdateProject(p); err != nil {
+		t.Errorf("TestUpdateProjectCrawlSettings: valid settings returned error %v", err)
+	}
+
+	invalid := []models.Project{
+		{Workers: 0},
+		{Workers: services.MaxWorkers + 1},
+		{Workers: 1, MinDelay: 500, MaxDelay: 100},
+		{Workers: 1, MaxDelay: services.MaxDelay + 1},
+		{Workers: 1, MaxRPS: -1},
+		{Workers: 1, RetryAttempts: services.MaxRetryAttempts + 1},
+		{Workers: 1, MaxURLs: -1},
+		{Workers: 1, MaxDuration: -1},
+		{Workers: 1, URLRules: "allow /blog/*"},
+		{Workers: 1, Headers: "X-Missing-Colon"},
+		{Workers: 1, Cookies: "invalid cookie"},
+		{Workers: 1, HostOverrides: "example.com 203.0.113.10"},
+		{Workers: 1, LocalPath: "../public"},
+		{Workers: 1, TrailingSlash: "both"},
+	}
+
+	for _, p := range invalid {
+		if err := service.UpdateProject(&p); err == nil {
+			t.Errorf("TestUpdateProjectCrawlSettings: %+v should return error", p)
+		}
+	}
+}
+
+func TestUpdateProjectSecretsWithoutKey(t *testing.T) {
+	service := services.NewProjectService(&storage{}, services.NewEncrypter(""))
+
+	p := &models.Project{URL: projectURL, Workers: 1}
+	if err := service.UpdateProject(p); err != nil {
+		t.Errorf("TestUpdateProjectSecretsWithoutKey: project without secrets returned error %v", err)
+	}
+
+	p.Headers = "X-Bypass-Cdn: secret"
+	if err := service.UpdateProject(p); err == nil {
+		t.Error("TestUpdateProjectSecretsWithoutKey: headers can't be stored without a secret key")
+	}
+}
+
+func TestParseStartURLs(t *testing.T) {
+	p := &models.Project{
+		URL:       projectURL,
+		StartURLs: "https://example.com/store\n\n  https://www.example.com/landing  \nhttps://example.com",
+	}
+
+	urls, err := services.ParseStartURLs(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"https://example.com/store", "https://www.example.com/landing", "https://example.com/"}
+	if len(urls) != len(expected) {
+		t.Fatalf("ParseStartURLs: %d != %d", len(urls), len(expected))
+	}
+
+	for i, u := range urls {
+		if u.String() != expected[i] {
+			t.Errorf("ParseStartURLs: %s != %s", u, expected[i])
+		}
+	}
+
+	invalid := []models.Project{
+		{URL: projectURL, StartURLs: "ftp://example.com/files"},
+		{URL: projectURL, StartURLs: "https://example.org/"},
+		{URL: projectURL, StartURLs: "https://shop.example.com/"},
+	}
+
+	for _, p := range invalid {
+		if _, err := services.ParseStartURLs(&p); err == nil {
+			t.Errorf("ParseStartURLs: %s should return error", p.StartURLs)
+		}
+	}
+
+	p = &models.Project{URL: projectURL, AllowSubdomains: true, StartURLs: "https://shop.example.com/"}
+	if _, err := services.ParseStartURLs(p); err != nil {
+		t.Errorf("ParseStartURLs: subdomain returned error %v", err)
+	}
+}
@@ -12,6 +12,8 @@ type (
 		FindProjectById(id int, uid int) (models.Project, error)
 
 		GetLastCrawl(*models.Project) models.Crawl
+
+		FindScheduleByProject(int64) (models.Schedule, error)
 	}
 
 	ProjectViewService struct {
@@ -55,9 +57,11 @@ func (s *ProjectViewService) GetProjectViews(uid int) []models.ProjectView {
 
 	projects := s.storage.FindProjectsByUser(uid)
 	for _, p := range projects {
+		schedule, _ := s.storage.FindScheduleByProject(p.Id)
 		pv := models.ProjectView{
-			Project: p,
-			Crawl:   s.storage.GetLastCrawl(&p),
+			Project:  p,
+			Crawl:    s.storage.GetLastCrawl(&p),
+			Schedule: schedule,
 		}
 		views = append(views, pv)
 	}
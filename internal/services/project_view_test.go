@@ -1,6 +1,7 @@
 package services_test
 
 import (
+	"database/sql"
 	"errors"
 	"testing"
 
@@ -49,6 +50,10 @@ func (s *testStorage) GetLastCrawl(p *models.Project) models.Crawl {
 	return models.Crawl{}
 }
 
+func (s *testStorage) FindScheduleByProject(pid int64) (models.Schedule, error) {
+	return models.Schedule{}, sql.ErrNoRows
+}
+
 var projectviewService = services.NewProjectViewService(&testStorage{})
 
 // TestGetProjectView tests the GetProjectView function of the projectview service.
@@ -2,8 +2,18 @@ package services
 
 import (
 	"errors"
+	"log"
+	"net/http"
+	"strings"
 
 	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/simhash"
+)
+
+const (
+	DefaultSimilarityThreshold = 90 // Min similarity percentage of near duplicate pages if it is not configured.
+
+	maxNearDuplicatePairs = 50 // Max number of pairs of similar pages shown in each near duplicate cluster.
 )
 
 type (
@@ -17,7 +27,7 @@ type (
 		FindSitemapPageReports(int64) <-chan *models.PageReport
 		FindLinks(pageReport *models.PageReport, cid int64, page int) []models.InternalLink
 		FindExternalLinks(pageReport *models.PageReport, cid int64, p int) []models.Link
-		FindPaginatedPageReports(cid int64, p int, term string) []models.PageReport
+		FindPaginatedPageReports(cid int64, p int, term string, hf *models.HeaderFilter) []models.PageReport
 
 		FindPageReportStyles(pageReport *models.PageReport, cid int64) []string
 		FindPageReportScripts(pageReport *models.PageReport, cid int64) []string
@@ -26,8 +36,13 @@ type (
 		FindPageReportIframes(pageReport *models.PageReport, cid int64) []string
 		FindPageReportImages(pageReport *models.PageReport, cid int64) []models.Image
 		FindPageReportHreflangs(pageReport *models.PageReport, cid int64) []models.Hreflang
+		FindPageReportRedirectHops(pageReport *models.PageReport, cid int64) []models.RedirectHop
+		FindPageReportHeaders(pageReport *models.PageReport, cid int64) []models.ResponseHeader
+		FindPageReportSnapshot(pageReport *models.PageReport, cid int64) []byte
+		FindPreviousSnapshot(pageReport *models.PageReport, cid int64) []byte
+		FindSimHashes(cid int64) []models.PageReport
 
-		GetNumberOfPagesForPageReport(cid int64, term string) int
+		GetNumberOfPagesForPageReport(cid int64, term string, hf *models.HeaderFilter) int
 		GetNumberOfPagesForInlinks(*models.PageReport, int64) int
 		GetNumberOfPagesForRedirecting(*models.PageReport, int64) int
 		GetNumberOfPagesForLinks(*models.PageReport, int64) int
@@ -35,12 +50,15 @@ type (
 	}
 
 	ReportService struct {
-		store ReportServiceStorage
+		store      ReportServiceStorage
+		similarity float64
 	}
 )
 
-func NewReportService(store ReportServiceStorage) *ReportService {
-	return &ReportService{store: store}
+// NewReportService returns a ReportService. The similarity, from 0 to 1, is the min
+// similarity of the pages in the near duplicate clusters.
+func NewReportService(store ReportServiceStorage, similarity float64) *ReportService {
+	return &ReportService{store: store, similarity: similarity}
 }
 
 // Returns a PageReportView by PageReport Id and Crawl Id.
@@ -52,6 +70,7 @@ func (s *ReportService) GetPageReport(rid int, crawlId int64, tab string, page i
 	}
 
 	v.PageReport.Hreflangs = s.store.FindPageReportHreflangs(&v.PageReport, crawlId)
+	v.PageReport.RedirectChain = s.store.FindPageReportRedirectHops(&v.PageReport, crawlId)
 
 	switch tab {
 	case "internal":
@@ -74,6 +93,16 @@ func (s *ReportService) GetPageReport(rid int, crawlId int64, tab string, page i
 		v.PageReport.Iframes = s.store.FindPageReportIframes(&v.PageReport, crawlId)
 	case "images":
 		v.PageReport.Images = s.store.FindPageReportImages(&v.PageReport, crawlId)
+	case "headers":
+		v.PageReport.Headers = s.store.FindPageReportHeaders(&v.PageReport, crawlId)
+	case "source":
+		v.Snapshot = s.snapshot(s.store.FindPageReportSnapshot(&v.PageReport, crawlId))
+	case "diff":
+		v.Snapshot = s.snapshot(s.store.FindPageReportSnapshot(&v.PageReport, crawlId))
+		previous := s.store.FindPreviousSnapshot(&v.PageReport, crawlId)
+		if v.Snapshot != "" && previous != nil {
+			v.Diff = DiffLines(s.snapshot(previous), v.Snapshot)
+		}
 	}
 
 	v.Paginator = s.getPaginator(&v.PageReport, crawlId, tab, page)
@@ -81,6 +110,21 @@ func (s *ReportService) GetPageReport(rid int, crawlId int64, tab string, page i
 	return v
 }
 
+// snapshot returns the decompressed snapshot, or an empty string if it can't be decompressed.
+func (s *ReportService) snapshot(compressed []byte) string {
+	if compressed == nil {
+		return ""
+	}
+
+	snapshot, err := decompressSnapshot(compressed)
+	if err != nil {
+		log.Printf("decompressSnapshot: %v\n", err)
+		return ""
+	}
+
+	return snapshot
+}
+
 // Returns the paginator for the specific "tab".
 func (s *ReportService) getPaginator(pageReport *models.PageReport, crawlId int64, tab string, page int) models.Paginator {
 	paginator := models.Paginator{
@@ -121,9 +165,11 @@ func (s *ReportService) GetPageReporsByIssueType(crawlId int64, eid string) <-ch
 }
 
 // Returns a PaginatorView with the corresponding page reports.
+// The search term can include a response header filter, see ParseExplorerTerm.
 func (s *ReportService) GetPaginatedReports(crawlId int64, currentPage int, term string) (models.PaginatorView, error) {
+	term, hf := ParseExplorerTerm(term)
 	paginator := models.Paginator{
-		TotalPages:  s.store.GetNumberOfPagesForPageReport(crawlId, term),
+		TotalPages:  s.store.GetNumberOfPagesForPageReport(crawlId, term, hf),
 		CurrentPage: currentPage,
 	}
 
@@ -141,13 +187,90 @@ func (s *ReportService) GetPaginatedReports(crawlId int64, currentPage int, term
 
 	paginatorView := models.PaginatorView{
 		Paginator:   paginator,
-		PageReports: s.store.FindPaginatedPageReports(crawlId, currentPage, term),
+		PageReports: s.store.FindPaginatedPageReports(crawlId, currentPage, term, hf),
 	}
 
 	return paginatorView, nil
 }
 
+// GetNearDuplicates returns the clusters of pages with similar text in the crawl, sorted by size.
+// Only the most similar pairs of each cluster are included.
+func (s *ReportService) GetNearDuplicates(crawlId int64) []models.NearDuplicateCluster {
+	pageReports := make(map[int64]models.PageReport)
+	items := []simhash.Item{}
+	for _, p := range s.store.FindSimHashes(crawlId) {
+		pageReports[p.Id] = p
+		items = append(items, simhash.Item{Id: p.Id, Hash: p.SimHash})
+	}
+
+	clusters := []models.NearDuplicateCluster{}
+	for _, c := range simhash.Clusters(items, s.similarity) {
+		cluster := models.NearDuplicateCluster{}
+		for _, id := range c.Ids {
+			cluster.PageReports = append(cluster.PageReports, pageReports[id])
+		}
+
+		for _, p := range c.Pairs[:min(len(c.Pairs), maxNearDuplicatePairs)] {
+			cluster.Pairs = append(cluster.Pairs, models.NearDuplicatePair{
+				A:          pageReports[p.A],
+				B:          pageReports[p.B],
+				Similarity: int(p.Similarity * 100),
+			})
+		}
+
+		clusters = append(clusters, cluster)
+	}
+
+	return clusters
+}
+
+// SimilarityThreshold returns the min similarity percentage of the near duplicate pages.
+func (s *ReportService) SimilarityThreshold() int {
+	return int(s.similarity * 100)
+}
+
 // Returns a channel of crawlable PageReports that can be included in a sitemap.
 func (s *ReportService) GetSitemapPageReports(crawlId int64) <-chan *models.PageReport {
 	return s.store.FindSitemapPageReports(crawlId)
 }
+
+// ParseExplorerTerm splits the explorer's search term into the URL search term and a
+// response header filter. The filter is written as "header:Name" to match the URLs with
+// the header, "header:Name=value" to match the URLs with a header containing the value, and
+// "-header:Name" to match the URLs without the header. The header filter is nil if the term
+// doesn't have one, and only the first one is used if there are many.
+func ParseExplorerTerm(term string) (string, *models.HeaderFilter) {
+	var hf *models.HeaderFilter
+	words := []string{}
+
+	for _, w := range strings.Fields(term) {
+		missing := strings.HasPrefix(w, "-")
+		h, ok := strings.CutPrefix(strings.TrimPrefix(w, "-"), "header:")
+		if !ok || h == "" {
+			words = append(words, w)
+			continue
+		}
+
+		if hf != nil {
+			continue
+		}
+
+		name, value, _ := strings.Cut(h, "=")
+		if name == "" {
+			words = append(words, w)
+			continue
+		}
+
+		hf = &models.HeaderFilter{
+			Name:    http.CanonicalHeaderKey(name),
+			Value:   value,
+			Missing: missing,
+		}
+
+		if missing {
+			hf.Value = ""
+		}
+	}
+
+	return strings.Join(words, " "), hf
+}
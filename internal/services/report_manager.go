@@ -24,6 +24,7 @@ type (
 		store              ReportManagerStorage
 		pageCallbacks      []*models.PageIssueReporter
 		multipageCallbacks []models.MultipageCallback
+		listModeCallbacks  []models.MultipageCallback
 	}
 )
 
@@ -47,6 +48,13 @@ func (rm *ReportManager) AddMultipageReporter(reporter models.MultipageCallback)
 	rm.multipageCallbacks = append(rm.multipageCallbacks, reporter)
 }
 
+// Add a multi-page issue reporter used in list mode crawls. As list mode crawls only include the
+// pages in the uploaded list, only the reporters that make sense on a subset of the site's pages
+// should be added.
+func (rm *ReportManager) AddListModeReporter(reporter models.MultipageCallback) {
+	rm.listModeCallbacks = append(rm.listModeCallbacks, reporter)
+}
+
 // CreatePageIssues loops the page reporters calling the callback function
 // and creating the issues found in the PageReport.
 func (r *ReportManager) CreatePageIssues(p *models.PageReport, htmlNode *html.Node, header *http.Header, crawl *models.Crawl) {
@@ -75,6 +83,7 @@ func (r *ReportManager) CreatePageIssues(p *models.PageReport, htmlNode *html.No
 }
 
 // CreateMultipageIssues uses the Reporters to create and save issues found in a crawl.
+// List mode crawls use the list mode reporters instead.
 func (r *ReportManager) CreateMultipageIssues(crawl *models.Crawl) {
 	iStream := make(chan *models.Issue)
 	wg := new(sync.WaitGroup)
@@ -85,7 +94,12 @@ func (r *ReportManager) CreateMultipageIssues(crawl *models.Crawl) {
 		wg.Done()
 	}()
 
-	for _, callback := range r.multipageCallbacks {
+	callbacks := r.multipageCallbacks
+	if crawl.ListMode {
+		callbacks = r.listModeCallbacks
+	}
+
+	for _, callback := range callbacks {
 		reporter := callback(crawl)
 		for pid := range reporter.Pstream {
 			iStream <- &models.Issue{
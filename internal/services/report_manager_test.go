@@ -150,3 +150,42 @@ func TestCreateMultiPageIssues(t *testing.T) {
 		t.Errorf("CreatePageIsssues: reporterCrawlId %d != %d", issue.ErrorType, reporterErrorType)
 	}
 }
+
+// Add a MultipageReporter and a ListModeReporter and test only the list mode reporter
+// is used in list mode crawls.
+func TestCreateMultiPageIssuesListMode(t *testing.T) {
+	storage := &mockStorage{}
+	service := services.NewReportManager(storage)
+
+	reporter := func(errorType int) models.MultipageCallback {
+		return func(c *models.Crawl) *models.MultipageIssueReporter {
+			stream := make(chan int64)
+
+			go func() {
+				stream <- pageReportId
+				close(stream)
+			}()
+
+			return &models.MultipageIssueReporter{
+				Pstream:   stream,
+				ErrorType: errorType,
+			}
+		}
+	}
+
+	listModeErrorType := reporterErrorType + 1
+	service.AddMultipageReporter(reporter(reporterErrorType))
+	service.AddListModeReporter(reporter(listModeErrorType))
+
+	crawl := &models.Crawl{Id: reporterCrawlId, ListMode: true}
+
+	service.CreateMultipageIssues(crawl)
+
+	if len(storage.Issues) != 1 {
+		t.Fatalf("CreateMultipageIssues: %d != 1", len(storage.Issues))
+	}
+
+	if storage.Issues[0].ErrorType != listModeErrorType {
+		t.Errorf("CreateMultipageIssues: ErrorType %d != %d", storage.Issues[0].ErrorType, listModeErrorType)
+	}
+}
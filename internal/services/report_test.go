@@ -117,11 +117,11 @@ func (s *reportstorage) FindSitemapPageReports(id int64) <-chan *models.PageRepo
 	return prStream
 }
 
-func (s *reportstorage) FindPaginatedPageReports(cid int64, p int, term string) []models.PageReport {
+func (s *reportstorage) FindPaginatedPageReports(cid int64, p int, term string, hf *models.HeaderFilter) []models.PageReport {
 	return []models.PageReport{}
 }
 
-func (s *reportstorage) GetNumberOfPagesForPageReport(cid int64, term string) int {
+func (s *reportstorage) GetNumberOfPagesForPageReport(cid int64, term string, hf *models.HeaderFilter) int {
 	return 0
 }
 
@@ -150,8 +150,28 @@ func (s *reportstorage) FindPageReportImages(pageReport *models.PageReport, cid
 func (s *reportstorage) FindPageReportHreflangs(pageReport *models.PageReport, cid int64) []models.Hreflang {
 	return []models.Hreflang{}
 }
+func (s *reportstorage) FindPageReportRedirectHops(pageReport *models.PageReport, cid int64) []models.RedirectHop {
+	return []models.RedirectHop{}
+}
+func (s *reportstorage) FindPageReportHeaders(pageReport *models.PageReport, cid int64) []models.ResponseHeader {
+	return []models.ResponseHeader{}
+}
+func (s *reportstorage) FindPageReportSnapshot(pageReport *models.PageReport, cid int64) []byte {
+	return nil
+}
+func (s *reportstorage) FindPreviousSnapshot(pageReport *models.PageReport, cid int64) []byte {
+	return nil
+}
+
+func (s *reportstorage) FindSimHashes(cid int64) []models.PageReport {
+	return []models.PageReport{
+		{Id: 1, URL: "https://example.com/a", SimHash: 0xf0f0f0f0f0f0f0f0},
+		{Id: 2, URL: "https://example.com/b", SimHash: 0xf0f0f0f0f0f0f0f1},
+		{Id: 3, URL: "https://example.com/c", SimHash: 0x0f0f0f0f0f0f0f0f},
+	}
+}
 
-var reportservice = services.NewReportService(&reportstorage{})
+var reportservice = services.NewReportService(&reportstorage{}, 0.9)
 
 func TestGetSitemapPageReports(t *testing.T) {
 	prStream := reportservice.GetSitemapPageReports(crawlId)
@@ -215,3 +235,48 @@ func TestGetPageReport(t *testing.T) {
 		t.Errorf("v.Redirects: %d != 1", len(vr.Redirects))
 	}
 }
+
+func TestParseExplorerTerm(t *testing.T) {
+	table := []struct {
+		term   string
+		search string
+		filter *models.HeaderFilter
+	}{
+		{"example", "example", nil},
+		{"header:server=Apache", "", &models.HeaderFilter{Name: "Server", Value: "Apache"}},
+		{"blog -header:cache-control", "blog", &models.HeaderFilter{Name: "Cache-Control", Missing: true}},
+		{"header:X-Powered-By blog", "blog", &models.HeaderFilter{Name: "X-Powered-By"}},
+		{"header:", "header:", nil},
+	}
+
+	for _, v := range table {
+		search, filter := services.ParseExplorerTerm(v.term)
+		if search != v.search {
+			t.Errorf("ParseExplorerTerm %q: search %q != %q", v.term, search, v.search)
+		}
+
+		if (filter == nil) != (v.filter == nil) || (filter != nil && *filter != *v.filter) {
+			t.Errorf("ParseExplorerTerm %q: filter %v != %v", v.term, filter, v.filter)
+		}
+	}
+}
+
+func TestGetNearDuplicates(t *testing.T) {
+	clusters := reportservice.GetNearDuplicates(crawlId)
+	if len(clusters) != 1 {
+		t.Fatalf("GetNearDuplicates: %d clusters != 1", len(clusters))
+	}
+
+	if len(clusters[0].PageReports) != 2 || len(clusters[0].Pairs) != 1 {
+		t.Fatalf("GetNearDuplicates: %v", clusters[0])
+	}
+
+	pair := clusters[0].Pairs[0]
+	if pair.A.URL != "https://example.com/a" || pair.B.URL != "https://example.com/b" {
+		t.Errorf("Pair %s %s", pair.A.URL, pair.B.URL)
+	}
+
+	if pair.Similarity != 98 {
+		t.Errorf("Similarity %d != 98", pair.Similarity)
+	}
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// Max number of versions of a robots.txt file shown in its history.
+const robotsHistoryLimit = 10
+
+// User agents suggested in the robots.txt tester.
+var robotsAgents = []string{"Googlebot", "Googlebot-Image", "Bingbot", "DuckDuckBot", "YandexBot", "Applebot", "GPTBot", "*"}
+
+type (
+	RobotsServiceStorage interface {
+		FindRobotsTxt(cid int64) []models.RobotsTxt
+		FindRobotsTxtHistory(pid int64, host string, limit int) []models.RobotsTxt
+	}
+
+	RobotsService struct {
+		store RobotsServiceStorage
+	}
+)
+
+func NewRobotsService(s RobotsServiceStorage) *RobotsService {
+	return &RobotsService{store: s}
+}
+
+// GetRobotsView returns the robots.txt files of the project's last crawl. It loads the history of
+// the host's file, which defaults to the project's host, and the changes of the selected version
+// against the previous one. The version is the crawl id of the selected version, zero selects the
+// most recent one. If testURL is not empty it is tested against the robots.txt rules for the agent.
+func (s *RobotsService) GetRobotsView(pv *models.ProjectView, host string, version int64, testURL, agent string) *models.RobotsView {
+	view := &models.RobotsView{
+		ProjectView: pv,
+		Hosts:       []string{},
+		History:     []models.RobotsTxtVersion{},
+		Agents:      robotsAgents,
+	}
+
+	files := s.store.FindRobotsTxt(pv.Crawl.Id)
+	for _, f := range files {
+		view.Hosts = append(view.Hosts, f.Host)
+	}
+
+	if host == "" {
+		host = defaultRobotsHost(pv.Project.URL, view.Hosts)
+	}
+
+	if host != "" {
+		history := s.store.FindRobotsTxtHistory(pv.Project.Id, host, robotsHistoryLimit+1)
+		selected := 0
+		for i, h := range history {
+			if h.CrawlId == version {
+				selected = i
+			}
+
+			if i < robotsHistoryLimit {
+				changed := i+1 < len(history) && (h.Body != history[i+1].Body || h.StatusCode != history[i+1].StatusCode)
+				view.History = append(view.History, models.RobotsTxtVersion{RobotsTxt: h, Changed: changed})
+			}
+		}
+
+		if len(history) > 0 {
+			view.RobotsTxt = &history[selected]
+		}
+
+		if selected+1 < len(history) {
+			view.Previous = &history[selected+1]
+			view.Diff = DiffLines(view.Previous.Body, view.RobotsTxt.Body)
+		}
+	}
+
+	if testURL != "" {
+		if view.RobotsTxt != nil {
+			files = append([]models.RobotsTxt{*view.RobotsTxt}, files...)
+		}
+
+		view.Test = TestRobotsTxt(files, testURL, agent)
+	}
+
+	return view
+}
+
+// TestRobotsTxt tests the URL against the rules for the user agent in the robots.txt file of
+// the URL's host. The first file of the host is used if there are many. All URLs are allowed
+// if the host's robots.txt file doesn't exist or can't be retrieved, as the crawler does.
+func TestRobotsTxt(files []models.RobotsTxt, rawURL, agent string) *models.RobotsTest {
+	t := &models.RobotsTest{URL: rawURL, Agent: agent}
+	if strings.TrimSpace(agent) == "" {
+		t.Error = "The user agent is required."
+		return t
+	}
+
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		t.Error = "The URL is not a valid http or https URL."
+		return t
+	}
+
+	t.Host = u.Host
+
+	var file *models.RobotsTxt
+	for i := range files {
+		if files[i].Host == u.Host {
+			file = &files[i]
+			break
+		}
+	}
+
+	if file == nil {
+		t.Error = "The robots.txt file of " + u.Host + " was not requested in this crawl."
+		return t
+	}
+
+	t.StatusCode = file.StatusCode
+	if file.StatusCode != 200 {
+		t.Allowed = true
+		return t
+	}
+
+	m := crawler.MatchRobotsRules([]byte(file.Body), agent, u)
+	t.Allowed = m.Allowed
+	t.Group = m.Group
+	t.Line = m.Line
+	t.Rule = m.Rule
+
+	return t
+}
+
+// defaultRobotsHost returns the project's host if it is one of the hosts, otherwise it
+// returns the first host. It returns an empty string if there are no hosts.
+func defaultRobotsHost(projectURL string, hosts []string) string {
+	if len(hosts) == 0 {
+		return ""
+	}
+
+	if u, err := url.Parse(projectURL); err == nil {
+		for _, h := range hosts {
+			if h == u.Host {
+				return h
+			}
+		}
+	}
+
+	return hosts[0]
+}
+
+// robotsTxt returns the crawl's robots.txt files from the ones requested by the crawler.
+func robotsTxt(files []crawler.RobotsFile) []models.RobotsTxt {
+	robots := []models.RobotsTxt{}
+	for _, f := range files {
+		robots = append(robots, models.RobotsTxt{
+			Host:       f.Host,
+			URL:        f.URL,
+			StatusCode: f.StatusCode,
+			Body:       strings.ToValidUTF8(string(f.Body), "\uFFFD"),
+			Overridden: f.Overridden,
+		})
+	}
+
+	return robots
+}
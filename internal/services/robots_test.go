@@ -0,0 +1,97 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/services"
+)
+
+type robotsTestStorage struct {
+	files   []models.RobotsTxt
+	history []models.RobotsTxt
+}
+
+func (s *robotsTestStorage) FindRobotsTxt(cid int64) []models.RobotsTxt {
+	return s.files
+}
+
+func (s *robotsTestStorage) FindRobotsTxtHistory(pid int64, host string, limit int) []models.RobotsTxt {
+	return s.history
+}
+
+func TestTestRobotsTxt(t *testing.T) {
+	files := []models.RobotsTxt{
+		{Host: "example.com", StatusCode: 200, Body: "User-agent: *\nDisallow: /private"},
+		{Host: "blog.example.com", StatusCode: 404},
+	}
+
+	table := []struct {
+		url     string
+		agent   string
+		allowed bool
+		line    int
+		err     bool
+	}{
+		{"https://example.com/private/page", "Googlebot", false, 2, false},
+		{"https://example.com/page", "Googlebot", true, 0, false},
+		{"https://blog.example.com/private", "Googlebot", true, 0, false},
+		{"https://other.example.com/", "Googlebot", false, 0, true},
+		{"example.com/private", "Googlebot", false, 0, true},
+		{"https://example.com/private", " ", false, 0, true},
+	}
+
+	for _, v := range table {
+		r := services.TestRobotsTxt(files, v.url, v.agent)
+		if (r.Error != "") != v.err {
+			t.Errorf("%s: expected error %v, got %q", v.url, v.err, r.Error)
+			continue
+		}
+
+		if r.Error == "" && (r.Allowed != v.allowed || r.Line != v.line) {
+			t.Errorf("%s: expected allowed %v line %d, got %+v", v.url, v.allowed, v.line, r)
+		}
+	}
+}
+
+func TestGetRobotsView(t *testing.T) {
+	storage := &robotsTestStorage{
+		files: []models.RobotsTxt{
+			{CrawlId: 3, Host: "cdn.example.com", StatusCode: 404},
+			{CrawlId: 3, Host: "example.com", StatusCode: 200, Body: "User-agent: *\nDisallow: /b"},
+		},
+		history: []models.RobotsTxt{
+			{CrawlId: 3, Host: "example.com", StatusCode: 200, Body: "User-agent: *\nDisallow: /b"},
+			{CrawlId: 2, Host: "example.com", StatusCode: 200, Body: "User-agent: *\nDisallow: /a"},
+			{CrawlId: 1, Host: "example.com", StatusCode: 200, Body: "User-agent: *\nDisallow: /a"},
+		},
+	}
+
+	s := services.NewRobotsService(storage)
+	pv := &models.ProjectView{
+		Project: models.Project{Id: 1, URL: "https://example.com"},
+		Crawl:   models.Crawl{Id: 3},
+	}
+
+	v := s.GetRobotsView(pv, "", 0, "https://example.com/a", "Googlebot")
+	if v.RobotsTxt == nil || v.RobotsTxt.CrawlId != 3 || v.Previous == nil || v.Previous.CrawlId != 2 {
+		t.Fatalf("expected the last version of example.com compared to the previous one, got %+v", v)
+	}
+
+	if len(v.History) != 3 || !v.History[0].Changed || v.History[1].Changed || v.History[2].Changed {
+		t.Errorf("unexpected history %+v", v.History)
+	}
+
+	if v.Test == nil || !v.Test.Allowed {
+		t.Errorf("expected URL to be allowed by the last version, got %+v", v.Test)
+	}
+
+	v = s.GetRobotsView(pv, "example.com", 2, "https://example.com/a", "Googlebot")
+	if v.RobotsTxt == nil || v.RobotsTxt.CrawlId != 2 || v.Previous == nil || v.Previous.CrawlId != 1 {
+		t.Fatalf("expected the selected version compared to the previous one, got %+v", v)
+	}
+
+	if v.Test == nil || v.Test.Allowed {
+		t.Errorf("expected URL to be blocked by the selected version, got %+v", v.Test)
+	}
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+const SchedulerInterval = 60 // Seconds between checks for due scheduled crawls.
+
+type (
+	SchedulerServiceStorage interface {
+		SaveSchedule(*models.Schedule) error
+		FindScheduleByProject(int64) (models.Schedule, error)
+		FindDueSchedules(time.Time) []models.Schedule
+		UpdateScheduleRuns(*models.Schedule)
+		DeleteSchedule(int64)
+	}
+
+	SchedulerServicesContainer struct {
+		ProjectService *ProjectService
+		CrawlerService *CrawlerService
+	}
+
+	SchedulerService struct {
+		storage        SchedulerServiceStorage
+		projectService *ProjectService
+		crawlerService *CrawlerService
+	}
+)
+
+func NewSchedulerService(s SchedulerServiceStorage, services SchedulerServicesContainer) *SchedulerService {
+	return &SchedulerService{
+		storage:        s,
+		projectService: services.ProjectService,
+		crawlerService: services.CrawlerService,
+	}
+}
+
+// Start checks for due scheduled crawls periodically in a new goroutine.
+func (s *SchedulerService) Start() {
+	go func() {
+		ticker := time.NewTicker(SchedulerInterval * time.Second)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			s.RunDue(time.Now())
+		}
+	}()
+}
+
+// RunDue starts the crawls of the schedules that are due at time t and sets their next run.
+// Projects that require credentials can't be crawled without a user entering them, and
+// projects that are still being crawled are skipped until their next run. Projects with an
+// interrupted crawl are also skipped, as starting a new crawl would discard it before the
+// user has a chance to resume it.
+func (s *SchedulerService) RunDue(t time.Time) {
+	for _, schedule := range s.storage.FindDueSchedules(t) {
+		s.run(schedule, t)
+	}
+}
+
+// run starts the scheduled crawl of a project and updates the schedule's next and last runs.
+func (s *SchedulerService) run(schedule models.Schedule, t time.Time) {
+	next, err := NextScheduleRun(schedule, t)
+	if err != nil {
+		log.Printf("Scheduler: pid %d %v\n", schedule.ProjectId, err)
+	}
+
+	schedule.NextRun = next
+	defer s.storage.UpdateScheduleRuns(&schedule)
+
+	p, err := s.projectService.FindProject(int(schedule.ProjectId), schedule.UserId)
+	if err != nil {
+		log.Printf("Scheduler: pid %d %v\n", schedule.ProjectId, err)
+		return
+	}
+
+	if p.RequiresCredentials() {
+		log.Printf("Scheduler: skipping %s, the project requires credentials", p.URL)
+		return
+	}
+
+	if s.crawlerService.IsCrawling(p) {
+		log.Printf("Scheduler: skipping %s, the project is still being crawled", p.URL)
+		return
+	}
+
+	if s.crawlerService.IsInterrupted(p) {
+		log.Printf("Scheduler: skipping %s, the project's last crawl was interrupted", p.URL)
+		return
+	}
+
+	if err := s.crawlerService.StartCrawler(p, models.BasicAuth{}); err != nil {
+		log.Printf("Scheduler: pid %d %v\n", p.Id, err)
+		return
+	}
+
+	schedule.LastRun = t
+}
+
+// SaveSchedule validates the schedule and stores it with its next run time.
+func (s *SchedulerService) SaveSchedule(schedule *models.Schedule) error {
+	next, err := NextScheduleRun(*schedule, time.Now())
+	if err != nil {
+		return err
+	}
+
+	schedule.NextRun = next
+
+	return s.storage.SaveSchedule(schedule)
+}
+
+// FindSchedule returns the project's crawl schedule.
+func (s *SchedulerService) FindSchedule(p models.Project) (models.Schedule, error) {
+	return s.storage.FindScheduleByProject(p.Id)
+}
+
+// DeleteSchedule removes the project's crawl schedule.
+func (s *SchedulerService) DeleteSchedule(p models.Project) {
+	s.storage.DeleteSchedule(p.Id)
+}
+
+// NextScheduleRun returns the first time after t the schedule should run. If the schedule
+// has a time window and the run falls outside of it, the run is delayed until the window opens.
+// It returns an error if the schedule is not valid or if it never runs.
+func NextScheduleRun(schedule models.Schedule, t time.Time) (time.Time, error) {
+	expr, err := scheduleExpression(schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	c, err := ParseCron(expr)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	next := c.Next(t)
+	if next.IsZero() {
+		return next, errors.New("the schedule never runs")
+	}
+
+	if schedule.WindowStart == "" && schedule.WindowEnd == "" {
+		return next, nil
+	}
+
+	start, err := parseClock(schedule.WindowStart)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	end, err := parseClock(schedule.WindowEnd)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if start == end {
+		return time.Time{}, errors.New("the time window is empty")
+	}
+
+	return nextInWindow(next, start, end), nil
+}
+
+// scheduleExpression returns the cron expression of the schedule's frequency.
+func scheduleExpression(schedule models.Schedule) (string, error) {
+	switch schedule.Frequency {
+	case models.ScheduleDaily, models.ScheduleWeekly:
+		m, err := parseClock(schedule.Time)
+		if err != nil {
+			return "", err
+		}
+
+		if schedule.Frequency == models.ScheduleDaily {
+			return fmt.Sprintf("%d %d * * *", m%60, m/60), nil
+		}
+
+		if schedule.Weekday < 0 || schedule.Weekday > 6 {
+			return "", errors.New("weekday out of range")
+		}
+
+		return fmt.Sprintf("%d %d * * %d", m%60, m/60, schedule.Weekday), nil
+	case models.ScheduleCron:
+		return schedule.Cron, nil
+	}
+
+	return "", fmt.Errorf("unknown schedule frequency %q", schedule.Frequency)
+}
+
+// parseClock returns the minutes since midnight of a time in "15:04" format.
+func parseClock(s string) (int, error) {
+	c, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+
+	return c.Hour()*60 + c.Minute(), nil
+}
+
+// nextInWindow returns t if it is within the time window, otherwise it returns the time
+// the window opens next. The window's start and end are minutes since midnight and the
+// window wraps around midnight if it ends before it starts.
+func nextInWindow(t time.Time, start, end int) time.Time {
+	m := t.Hour()*60 + t.Minute()
+
+	in := m >= start && m < end
+	if start > end {
+		in = m >= start || m < end
+	}
+
+	if in {
+		return t
+	}
+
+	w := time.Date(t.Year(), t.Month(), t.Day(), start/60, start%60, 0, 0, t.Location())
+	if w.Before(t) {
+		w = w.AddDate(0, 0, 1)
+	}
+
+	return w
+}
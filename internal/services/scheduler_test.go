@@ -0,0 +1,136 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/services"
+)
+
+func TestNextScheduleRun(t *testing.T) {
+	// Wednesday, January 10 2024.
+	from := time.Date(2024, time.January, 10, 10, 30, 0, 0, time.UTC)
+
+	table := []struct {
+		schedule models.Schedule
+		want     time.Time
+	}{
+		{
+			models.Schedule{Frequency: models.ScheduleDaily, Time: "12:00"},
+			time.Date(2024, time.January, 10, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			models.Schedule{Frequency: models.ScheduleDaily, Time: "09:15"},
+			time.Date(2024, time.January, 11, 9, 15, 0, 0, time.UTC),
+		},
+		{
+			models.Schedule{Frequency: models.ScheduleWeekly, Time: "08:00", Weekday: 1},
+			time.Date(2024, time.January, 15, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			models.Schedule{Frequency: models.ScheduleCron, Cron: "0 * * * *"},
+			time.Date(2024, time.January, 10, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			// The run is delayed until the window opens.
+			models.Schedule{Frequency: models.ScheduleDaily, Time: "12:00", WindowStart: "22:00", WindowEnd: "06:00"},
+			time.Date(2024, time.January, 10, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			// The run is within a window crossing midnight.
+			models.Schedule{Frequency: models.ScheduleDaily, Time: "02:00", WindowStart: "22:00", WindowEnd: "06:00"},
+			time.Date(2024, time.January, 11, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			// The window opens on the next day.
+			models.Schedule{Frequency: models.ScheduleCron, Cron: "0 * * * *", WindowStart: "08:00", WindowEnd: "10:00"},
+			time.Date(2024, time.January, 11, 8, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range table {
+		got, err := services.NextScheduleRun(tc.schedule, from)
+		if err != nil {
+			t.Errorf("%+v: %v", tc.schedule, err)
+			continue
+		}
+
+		if !got.Equal(tc.want) {
+			t.Errorf("%+v: want %v, got %v", tc.schedule, tc.want, got)
+		}
+	}
+}
+
+func TestNextScheduleRunErrors(t *testing.T) {
+	from := time.Date(2024, time.January, 10, 10, 30, 0, 0, time.UTC)
+
+	table := []models.Schedule{
+		{Frequency: "monthly", Time: "12:00"},
+		{Frequency: models.ScheduleDaily, Time: "25:00"},
+		{Frequency: models.ScheduleWeekly, Time: "12:00", Weekday: 7},
+		{Frequency: models.ScheduleCron, Cron: "* * *"},
+		{Frequency: models.ScheduleCron, Cron: "0 0 31 2 *"},
+		{Frequency: models.ScheduleDaily, Time: "12:00", WindowStart: "22:00"},
+		{Frequency: models.ScheduleDaily, Time: "12:00", WindowStart: "22:00", WindowEnd: "22:00"},
+	}
+
+	for _, s := range table {
+		if _, err := services.NextScheduleRun(s, from); err == nil {
+			t.Errorf("%+v: expected error", s)
+		}
+	}
+}
+
+type scheduleStorage struct {
+	services.SchedulerServiceStorage
+	schedules []models.Schedule
+	updated   []models.Schedule
+}
+
+func (s *scheduleStorage) FindDueSchedules(time.Time) []models.Schedule {
+	return s.schedules
+}
+
+func (s *scheduleStorage) UpdateScheduleRuns(schedule *models.Schedule) {
+	s.updated = append(s.updated, *schedule)
+}
+
+// interruptedStorage reports an interrupted last crawl. Any other call, such as
+// deleting the crawl or saving a new one, panics through the nil embedded interface.
+type interruptedStorage struct {
+	services.CrawlerServiceStorage
+}
+
+func (s *interruptedStorage) GetLastCrawl(*models.Project) models.Crawl {
+	return models.Crawl{Interrupted: true}
+}
+
+// TestRunDueInterrupted tests that a due schedule doesn't start a new crawl while the
+// project's last crawl is interrupted, as it would discard the crawl before it is resumed.
+func TestRunDueInterrupted(t *testing.T) {
+	now := time.Date(2024, time.January, 10, 12, 0, 0, 0, time.UTC)
+	store := &scheduleStorage{
+		schedules: []models.Schedule{
+			{ProjectId: gid, UserId: guid, Frequency: models.ScheduleDaily, Time: "12:00", NextRun: now},
+		},
+	}
+
+	scheduler := services.NewSchedulerService(store, services.SchedulerServicesContainer{
+		ProjectService: service,
+		CrawlerService: services.NewCrawlerService(&interruptedStorage{}, services.CrawlerServicesContainer{}),
+	})
+	scheduler.RunDue(now)
+
+	if len(store.updated) != 1 {
+		t.Fatalf("updated schedules: %d want: 1", len(store.updated))
+	}
+
+	if !store.updated[0].LastRun.IsZero() {
+		t.Errorf("LastRun: %v want: zero", store.updated[0].LastRun)
+	}
+
+	if !store.updated[0].NextRun.After(now) {
+		t.Errorf("NextRun: %v want after %v", store.updated[0].NextRun, now)
+	}
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// Max number of differences between two snapshots the diff looks for. If the snapshots
+// differ more, the differing lines are shown as removed and added in two blocks.
+const maxDiffEdits = 1000
+
+// compressSnapshot returns the gzip compressed body.
+func compressSnapshot(body []byte) ([]byte, error) {
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	if _, err := w.Write(body); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// decompressSnapshot returns the decompressed snapshot as a string.
+func decompressSnapshot(snapshot []byte) (string, error) {
+	r, err := gzip.NewReader(bytes.NewReader(snapshot))
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+
+	return string(b), err
+}
+
+// DiffLines returns the line by line differences between the old and new texts.
+// The common lines at the start and end of the texts are skipped before the diff
+// is calculated using Myers' algorithm.
+func DiffLines(old, new string) []models.DiffLine {
+	a := splitLines(old)
+	b := splitLines(new)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := []models.DiffLine{}
+	for _, l := range a[:prefix] {
+		lines = append(lines, models.DiffLine{Type: models.DiffEqual, Text: l})
+	}
+
+	lines = append(lines, myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+
+	for _, l := range a[len(a)-suffix:] {
+		lines = append(lines, models.DiffLine{Type: models.DiffEqual, Text: l})
+	}
+
+	return lines
+}
+
+// splitLines splits a text in lines, it returns an empty slice if the text is empty.
+func splitLines(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// myersDiff returns the shortest edit script to transform a into b. If it needs more than
+// maxDiffEdits edits all the lines of a are removed and all the lines of b are added.
+func myersDiff(a, b []string) []models.DiffLine {
+	n, m := len(a), len(b)
+	max := min(n+m, maxDiffEdits)
+
+	// The trace stores a copy of the furthest reaching x of each diagonal k for every
+	// number of edits d, so the path can be followed back once the end is reached.
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	trace := [][]int{}
+
+	for d := 0; d <= max; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				trace = append(trace, append([]int{}, v...))
+				return backtrack(a, b, trace, offset)
+			}
+		}
+
+		trace = append(trace, append([]int{}, v...))
+	}
+
+	lines := []models.DiffLine{}
+	for _, l := range a {
+		lines = append(lines, models.DiffLine{Type: models.DiffDelete, Text: l})
+	}
+
+	for _, l := range b {
+		lines = append(lines, models.DiffLine{Type: models.DiffInsert, Text: l})
+	}
+
+	return lines
+}
+
+// backtrack follows the trace of the Myers' algorithm back from the end of both texts
+// and returns the diff lines.
+func backtrack(a, b []string, trace [][]int, offset int) []models.DiffLine {
+	x, y := len(a), len(b)
+	reversed := []models.DiffLine{}
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		k := x - y
+
+		var prevK int
+		if d == 0 {
+			prevK = 0
+		} else if k == -d || (k != d && trace[d-1][offset+k-1] < trace[d-1][offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := 0
+		if d > 0 {
+			prevX = trace[d-1][offset+prevK]
+		}
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, models.DiffLine{Type: models.DiffEqual, Text: a[x]})
+		}
+
+		if d == 0 {
+			break
+		}
+
+		if x == prevX {
+			y--
+			reversed = append(reversed, models.DiffLine{Type: models.DiffInsert, Text: b[y]})
+		} else {
+			x--
+			reversed = append(reversed, models.DiffLine{Type: models.DiffDelete, Text: a[x]})
+		}
+	}
+
+	lines := make([]models.DiffLine, len(reversed))
+	for i, l := range reversed {
+		lines[len(reversed)-1-i] = l
+	}
+
+	return lines
+}
@@ -0,0 +1,72 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/services"
+)
+
+func TestDiffLines(t *testing.T) {
+	old := "<html>\n<title>Old</title>\n<p>a</p>\n<p>b</p>\n</html>\n"
+	new := "<html>\n<title>New</title>\n<p>a</p>\n<p>c</p>\n<p>b</p>\n</html>\n"
+
+	expected := []models.DiffLine{
+		{Type: models.DiffEqual, Text: "<html>"},
+		{Type: models.DiffDelete, Text: "<title>Old</title>"},
+		{Type: models.DiffInsert, Text: "<title>New</title>"},
+		{Type: models.DiffEqual, Text: "<p>a</p>"},
+		{Type: models.DiffInsert, Text: "<p>c</p>"},
+		{Type: models.DiffEqual, Text: "<p>b</p>"},
+		{Type: models.DiffEqual, Text: "</html>"},
+	}
+
+	lines := services.DiffLines(old, new)
+	if len(lines) != len(expected) {
+		t.Fatalf("DiffLines: %d lines != %d\n%v", len(lines), len(expected), lines)
+	}
+
+	for i, l := range expected {
+		if lines[i] != l {
+			t.Errorf("DiffLines line %d: %v != %v", i, lines[i], l)
+		}
+	}
+}
+
+func TestDiffLinesEqual(t *testing.T) {
+	lines := services.DiffLines("a\nb", "a\nb")
+	for _, l := range lines {
+		if l.Type != models.DiffEqual {
+			t.Errorf("DiffLines: %v is not equal", l)
+		}
+	}
+
+	if len(services.DiffLines("", "")) != 0 {
+		t.Error("DiffLines: empty texts have lines")
+	}
+}
+
+func TestDiffLinesRebuildsTexts(t *testing.T) {
+	old := strings.Repeat("x\ny\nz\n", 50)
+	new := strings.Repeat("x\nz\nw\n", 40)
+
+	var a, b []string
+	for _, l := range services.DiffLines(old, new) {
+		if l.Type != models.DiffInsert {
+			a = append(a, l.Text)
+		}
+
+		if l.Type != models.DiffDelete {
+			b = append(b, l.Text)
+		}
+	}
+
+	if strings.Join(a, "\n")+"\n" != old {
+		t.Error("DiffLines: the old text can't be rebuilt from the diff")
+	}
+
+	if strings.Join(b, "\n")+"\n" != new {
+		t.Error("DiffLines: the new text can't be rebuilt from the diff")
+	}
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// ParseURLList returns the unique absolute http and https URLs in a list. Plain text lists
+// have one URL per line, while in CSV lists the first URL field of each record is used.
+// Lines without URLs, such as CSV headers, are ignored and the list is truncated at limit URLs.
+func ParseURLList(r io.Reader, isCSV bool, limit int) ([]*url.URL, error) {
+	urls := []*url.URL{}
+	seen := make(map[string]bool)
+
+	add := func(u *url.URL) {
+		if u == nil || seen[u.String()] || len(urls) >= limit {
+			return
+		}
+
+		seen[u.String()] = true
+		urls = append(urls, u)
+	}
+
+	if isCSV {
+		reader := csv.NewReader(r)
+		reader.LazyQuotes = true
+		reader.FieldsPerRecord = -1
+
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			for _, field := range record {
+				if u := parseListURL(field); u != nil {
+					add(u)
+					break
+				}
+			}
+		}
+
+		return urls, nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		add(parseListURL(scanner.Text()))
+	}
+
+	return urls, scanner.Err()
+}
+
+// parseListURL returns the parsed URL if s is an absolute http or https URL, otherwise it returns nil.
+func parseListURL(s string) *url.URL {
+	s = strings.TrimSpace(strings.TrimPrefix(s, "\uFEFF"))
+	if s == "" || strings.ContainsAny(s, " \t") {
+		return nil
+	}
+
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil
+	}
+
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	return u
+}
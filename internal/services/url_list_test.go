@@ -0,0 +1,61 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/services"
+)
+
+func TestParseURLList(t *testing.T) {
+	list := "https://example.com/a\n\nnot a url\nhttps://example.com\nftp://example.com/file\nhttps://example.com/a\nhttps://example.com/?q=1,2\n"
+
+	urls, err := services.ParseURLList(strings.NewReader(list), false, services.CrawlLimit)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := []string{"https://example.com/a", "https://example.com/", "https://example.com/?q=1,2"}
+	if len(urls) != len(expected) {
+		t.Fatalf("expected %d URLs, got %d", len(expected), len(urls))
+	}
+
+	for i, u := range urls {
+		if u.String() != expected[i] {
+			t.Errorf("URL %d should be %s, got %s", i, expected[i], u.String())
+		}
+	}
+}
+
+func TestParseURLListCSV(t *testing.T) {
+	list := "Title,Address,Status\n\"Home, page\",https://example.com/,200\nPost,https://example.com/post,301\nMissing,,404\n"
+
+	urls, err := services.ParseURLList(strings.NewReader(list), true, services.CrawlLimit)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := []string{"https://example.com/", "https://example.com/post"}
+	if len(urls) != len(expected) {
+		t.Fatalf("expected %d URLs, got %d", len(expected), len(urls))
+	}
+
+	for i, u := range urls {
+		if u.String() != expected[i] {
+			t.Errorf("URL %d should be %s, got %s", i, expected[i], u.String())
+		}
+	}
+}
+
+func TestParseURLListLimit(t *testing.T) {
+	list := "https://example.com/a\nhttps://example.com/b\nhttps://example.com/c\n"
+
+	urls, err := services.ParseURLList(strings.NewReader(list), false, 2)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if len(urls) != 2 {
+		t.Errorf("expected the list to be truncated at 2 URLs, got %d", len(urls))
+	}
+}
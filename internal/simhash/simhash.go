@@ -0,0 +1,174 @@
+// Package simhash implements SimHash fingerprints to find near duplicate texts.
+package simhash
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"sort"
+	"strings"
+)
+
+// Number of words in each of the shingles hashed to build the fingerprint.
+const shingleSize = 3
+
+// Item is a fingerprinted document identified by its id.
+type Item struct {
+	Id   int64
+	Hash uint64
+}
+
+// Pair is a pair of similar documents.
+type Pair struct {
+	A          int64
+	B          int64
+	Similarity float64
+}
+
+// Cluster is a group of similar documents along with the similarity of each pair
+// of documents that are similar enough.
+type Cluster struct {
+	Ids   []int64
+	Pairs []Pair
+}
+
+// Fingerprint returns the SimHash of the words. Each overlapping shingle of words is hashed
+// and every bit of the fingerprint is set if it is set in most of the shingle hashes.
+// It returns zero if there are no words.
+func Fingerprint(words []string) uint64 {
+	if len(words) == 0 {
+		return 0
+	}
+
+	var weights [64]int
+	h := fnv.New64a()
+	for i := 0; i+shingleSize <= len(words) || i == 0; i++ {
+		end := min(i+shingleSize, len(words))
+
+		h.Reset()
+		h.Write([]byte(strings.Join(words[i:end], " ")))
+		sum := h.Sum64()
+
+		for b := 0; b < 64; b++ {
+			if sum&(1<<b) != 0 {
+				weights[b]++
+			} else {
+				weights[b]--
+			}
+		}
+	}
+
+	var fingerprint uint64
+	for b := 0; b < 64; b++ {
+		if weights[b] > 0 {
+			fingerprint |= 1 << b
+		}
+	}
+
+	return fingerprint
+}
+
+// Similarity returns the fraction of equal bits in two fingerprints, from 0 to 1.
+func Similarity(a, b uint64) float64 {
+	return 1 - float64(bits.OnesCount64(a^b))/64
+}
+
+// Clusters groups the items that have a similarity of at least threshold with any other
+// item in the group. Only the groups with more than one item are returned, sorted by size.
+// The fingerprints are split in bands so only the items sharing a band are compared,
+// as two fingerprints differing in fewer bits than the number of bands must have
+// at least one equal band.
+func Clusters(items []Item, threshold float64) []Cluster {
+	maxDistance := int((1 - threshold) * 64)
+	bands := min(maxDistance+1, 64)
+	width := 64 / bands
+
+	parent := make([]int, len(items))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+
+		return parent[i]
+	}
+
+	seen := make(map[[2]int]bool)
+	pairs := []Pair{}
+	pairIndex := [][2]int{}
+
+	for band := 0; band < bands; band++ {
+		shift := band * width
+		mask := uint64(1)<<width - 1
+		if band == bands-1 {
+			mask = ^uint64(0) >> shift
+		}
+
+		buckets := make(map[uint64][]int)
+		for i, item := range items {
+			key := (item.Hash >> shift) & mask
+			buckets[key] = append(buckets[key], i)
+		}
+
+		for _, bucket := range buckets {
+			for x := 0; x < len(bucket); x++ {
+				for y := x + 1; y < len(bucket); y++ {
+					i, j := bucket[x], bucket[y]
+					if seen[[2]int{i, j}] {
+						continue
+					}
+					seen[[2]int{i, j}] = true
+
+					s := Similarity(items[i].Hash, items[j].Hash)
+					if s < threshold {
+						continue
+					}
+
+					pairs = append(pairs, Pair{A: items[i].Id, B: items[j].Id, Similarity: s})
+					pairIndex = append(pairIndex, [2]int{i, j})
+					parent[find(i)] = find(j)
+				}
+			}
+		}
+	}
+
+	groups := make(map[int]*Cluster)
+	for i, item := range items {
+		root := find(i)
+		if groups[root] == nil {
+			groups[root] = &Cluster{}
+		}
+		groups[root].Ids = append(groups[root].Ids, item.Id)
+	}
+
+	for n, p := range pairs {
+		root := find(pairIndex[n][0])
+		groups[root].Pairs = append(groups[root].Pairs, p)
+	}
+
+	clusters := []Cluster{}
+	for _, c := range groups {
+		if len(c.Ids) < 2 {
+			continue
+		}
+
+		sort.Slice(c.Pairs, func(i, j int) bool {
+			return c.Pairs[i].Similarity > c.Pairs[j].Similarity
+		})
+
+		clusters = append(clusters, *c)
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i].Ids) != len(clusters[j].Ids) {
+			return len(clusters[i].Ids) > len(clusters[j].Ids)
+		}
+
+		return clusters[i].Ids[0] < clusters[j].Ids[0]
+	})
+
+	return clusters
+}
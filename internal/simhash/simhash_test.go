@@ -0,0 +1,87 @@
+package simhash_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/simhash"
+)
+
+const text = `SEOnaut is an open source SEO auditing tool that crawls a website and checks its pages for
+broken links, redirect chains, missing titles and descriptions, duplicated content and many other
+issues that may prevent the site from ranking well in the search engines results pages`
+
+func TestFingerprint(t *testing.T) {
+	a := simhash.Fingerprint(strings.Fields(text))
+	if a == 0 {
+		t.Fatal("Fingerprint is 0")
+	}
+
+	if b := simhash.Fingerprint(strings.Fields(text)); a != b {
+		t.Errorf("Fingerprint of the same text %x != %x", a, b)
+	}
+
+	if f := simhash.Fingerprint([]string{}); f != 0 {
+		t.Errorf("Fingerprint of no words %x != 0", f)
+	}
+
+	similar := simhash.Fingerprint(strings.Fields(text + " updated on 2024-05-01"))
+	if s := simhash.Similarity(a, similar); s < 0.9 {
+		t.Errorf("Similarity of similar texts %f < 0.9", s)
+	}
+
+	different := simhash.Fingerprint(strings.Fields("The quick brown fox jumps over the lazy dog while the cat sleeps on the warm windowsill all afternoon long"))
+	if s := simhash.Similarity(a, different); s >= 0.9 {
+		t.Errorf("Similarity of different texts %f >= 0.9", s)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a    uint64
+		b    uint64
+		want float64
+	}{
+		{0, 0, 1},
+		{0, ^uint64(0), 0},
+		{0xff, 0x0f, 0.9375},
+	}
+
+	for _, tt := range tests {
+		if s := simhash.Similarity(tt.a, tt.b); s != tt.want {
+			t.Errorf("Similarity(%x, %x) %f != %f", tt.a, tt.b, s, tt.want)
+		}
+	}
+}
+
+func TestClusters(t *testing.T) {
+	items := []simhash.Item{
+		{Id: 1, Hash: 0xf0f0f0f0f0f0f0f0},
+		{Id: 2, Hash: 0xf0f0f0f0f0f0f0f1},
+		{Id: 3, Hash: 0xf0f0f0f0f0f0f0f3},
+		{Id: 4, Hash: 0x0f0f0f0f0f0f0f0f},
+		{Id: 5, Hash: 0x0f0f0f0f0f0f0f8f},
+		{Id: 6, Hash: 0x00000000ffffffff},
+	}
+
+	clusters := simhash.Clusters(items, 0.9)
+	if len(clusters) != 2 {
+		t.Fatalf("Clusters: %d != 2", len(clusters))
+	}
+
+	if len(clusters[0].Ids) != 3 || len(clusters[0].Pairs) != 3 {
+		t.Errorf("First cluster %v", clusters[0])
+	}
+
+	if clusters[0].Pairs[0].Similarity < clusters[0].Pairs[2].Similarity {
+		t.Errorf("Pairs not sorted by similarity %v", clusters[0].Pairs)
+	}
+
+	if len(clusters[1].Ids) != 2 || clusters[1].Ids[0] != 4 || clusters[1].Ids[1] != 5 {
+		t.Errorf("Second cluster %v", clusters[1])
+	}
+
+	if c := simhash.Clusters(items, 1); len(c) != 0 {
+		t.Errorf("Clusters with threshold 1: %d != 0", len(c))
+	}
+}
@@ -0,0 +1,4 @@
+ALTER TABLE `projects` DROP COLUMN `workers`;
+ALTER TABLE `projects` DROP COLUMN `min_delay`;
+ALTER TABLE `projects` DROP COLUMN `max_delay`;
+ALTER TABLE `projects` DROP COLUMN `max_rps`;
\ No newline at end of file
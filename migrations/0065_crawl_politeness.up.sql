@@ -0,0 +1,4 @@
+ALTER TABLE `projects` ADD COLUMN `workers` int NOT NULL DEFAULT 2;
+ALTER TABLE `projects` ADD COLUMN `min_delay` int NOT NULL DEFAULT 0;
+ALTER TABLE `projects` ADD COLUMN `max_delay` int NOT NULL DEFAULT 1500;
+ALTER TABLE `projects` ADD COLUMN `max_rps` float NOT NULL DEFAULT 0;
\ No newline at end of file
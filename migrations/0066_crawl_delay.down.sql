@@ -0,0 +1,2 @@
+ALTER TABLE `projects` DROP COLUMN `crawl_delay`;
+ALTER TABLE `crawls` DROP COLUMN `crawl_delay`;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE `projects` ADD COLUMN `crawl_delay` int NOT NULL DEFAULT 0;
+ALTER TABLE `crawls` ADD COLUMN `crawl_delay` int NOT NULL DEFAULT 0;
\ No newline at end of file
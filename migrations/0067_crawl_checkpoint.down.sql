@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS `crawl_queue`;
+DROP TABLE IF EXISTS `crawl_seen`;
+ALTER TABLE `crawls` DROP COLUMN `checkpoint`;
+ALTER TABLE `crawls` DROP COLUMN `checkpoint_pagereport`;
+ALTER TABLE `crawls` DROP COLUMN `interrupted`;
\ No newline at end of file
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS `crawl_queue` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `crawl_id` int unsigned NOT NULL,
+  `url` varchar(2048) NOT NULL DEFAULT '',
+  `depth` int NOT NULL DEFAULT 0,
+  `method` int NOT NULL DEFAULT 0,
+  `ignore_domain` tinyint NOT NULL DEFAULT 0,
+  PRIMARY KEY (`id`),
+  KEY `crawl_queue_crawl` (`crawl_id`),
+  CONSTRAINT `crawl_queue_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `crawl_seen` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `crawl_id` int unsigned NOT NULL,
+  `url` varchar(2048) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`),
+  KEY `crawl_seen_crawl` (`crawl_id`),
+  CONSTRAINT `crawl_seen_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE
+);
+
+ALTER TABLE `crawls` ADD COLUMN `checkpoint` timestamp NULL DEFAULT NULL;
+ALTER TABLE `crawls` ADD COLUMN `checkpoint_pagereport` int unsigned NOT NULL DEFAULT 0;
+ALTER TABLE `crawls` ADD COLUMN `interrupted` tinyint NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE `projects` DROP COLUMN `url_rules`;
+ALTER TABLE `crawls` DROP COLUMN `excluded_by_rule`;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE `projects` ADD COLUMN `url_rules` text;
+ALTER TABLE `crawls` ADD COLUMN `excluded_by_rule` int NOT NULL DEFAULT 0;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE `projects` DROP COLUMN `strip_params`, DROP COLUMN `sort_params`, DROP COLUMN `drop_fragment`, DROP COLUMN `lowercase_host`;
+ALTER TABLE `pagereports` DROP COLUMN `original_url`;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE `projects` ADD COLUMN `strip_params` text, ADD COLUMN `sort_params` tinyint NOT NULL DEFAULT '0', ADD COLUMN `drop_fragment` tinyint NOT NULL DEFAULT '0', ADD COLUMN `lowercase_host` tinyint NOT NULL DEFAULT '0';
+ALTER TABLE `pagereports` ADD COLUMN `original_url` varchar(2048) NOT NULL DEFAULT '';
\ No newline at end of file
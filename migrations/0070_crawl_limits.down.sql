@@ -0,0 +1,2 @@
+ALTER TABLE `projects` DROP COLUMN `max_depth`, DROP COLUMN `max_directory_urls`;
+ALTER TABLE `crawls` DROP COLUMN `exceeded_max_depth`, DROP COLUMN `exceeded_directory_limit`;
\ No newline at end of file
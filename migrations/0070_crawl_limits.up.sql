@@ -0,0 +1,2 @@
+ALTER TABLE `projects` ADD COLUMN `max_depth` int NOT NULL DEFAULT '0', ADD COLUMN `max_directory_urls` int NOT NULL DEFAULT '0';
+ALTER TABLE `crawls` ADD COLUMN `exceeded_max_depth` int NOT NULL DEFAULT '0', ADD COLUMN `exceeded_directory_limit` int NOT NULL DEFAULT '0';
\ No newline at end of file
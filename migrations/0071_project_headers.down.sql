@@ -0,0 +1 @@
+ALTER TABLE `projects` DROP COLUMN `headers`, DROP COLUMN `cookies`;
\ No newline at end of file
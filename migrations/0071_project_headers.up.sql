@@ -0,0 +1 @@
+ALTER TABLE `projects` ADD COLUMN `headers` text, ADD COLUMN `cookies` text;
\ No newline at end of file
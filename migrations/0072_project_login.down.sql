@@ -0,0 +1 @@
+ALTER TABLE `projects` DROP COLUMN `login_url`, DROP COLUMN `login_user_field`, DROP COLUMN `login_pass_field`, DROP COLUMN `bearer_auth`;
\ No newline at end of file
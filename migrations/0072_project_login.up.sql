@@ -0,0 +1 @@
+ALTER TABLE `projects` ADD COLUMN `login_url` varchar(2048) NOT NULL DEFAULT '', ADD COLUMN `login_user_field` varchar(255) NOT NULL DEFAULT '', ADD COLUMN `login_pass_field` varchar(255) NOT NULL DEFAULT '', ADD COLUMN `bearer_auth` tinyint NOT NULL DEFAULT '0';
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `redirect_hops`;
+DELETE FROM issue_types WHERE id IN (73, 74);
\ No newline at end of file
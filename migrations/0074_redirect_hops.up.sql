@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS `redirect_hops` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `pagereport_id` int unsigned NOT NULL,
+  `crawl_id` int unsigned NOT NULL,
+  `url` varchar(2048) NOT NULL DEFAULT '',
+  `status_code` int NOT NULL DEFAULT '0',
+  `location` varchar(2048) NOT NULL DEFAULT '',
+  `external` tinyint NOT NULL DEFAULT '0',
+  PRIMARY KEY (`id`),
+  KEY `redirect_hops_pagereport` (`pagereport_id`),
+  KEY `redirect_hops_crawl` (`crawl_id`),
+  CONSTRAINT `redirect_hops_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `redirect_hops_pagereport` FOREIGN KEY (`pagereport_id`) REFERENCES `pagereports` (`id`) ON DELETE CASCADE
+);
+
+INSERT INTO issue_types (id, type, priority) VALUES(73, "ERROR_REDIRECT_EXTERNAL", 2);
+INSERT INTO issue_types (id, type, priority) VALUES(74, "ERROR_REDIRECT_TOO_LONG", 1);
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE `projects` DROP COLUMN `retry_attempts`;
+ALTER TABLE `pagereports` DROP COLUMN `attempts`;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE `projects` ADD COLUMN `retry_attempts` int NOT NULL DEFAULT '3';
+ALTER TABLE `pagereports` ADD COLUMN `attempts` int NOT NULL DEFAULT '1';
\ No newline at end of file
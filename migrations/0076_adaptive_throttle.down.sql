@@ -0,0 +1 @@
+ALTER TABLE `projects` DROP COLUMN `adaptive_throttle`;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `projects` ADD COLUMN `adaptive_throttle` tinyint NOT NULL DEFAULT '0';
\ No newline at end of file
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS `schedules` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `project_id` int unsigned NOT NULL,
+  `frequency` varchar(16) NOT NULL DEFAULT '',
+  `time` varchar(5) NOT NULL DEFAULT '',
+  `weekday` int NOT NULL DEFAULT '0',
+  `cron` varchar(256) NOT NULL DEFAULT '',
+  `window_start` varchar(5) NOT NULL DEFAULT '',
+  `window_end` varchar(5) NOT NULL DEFAULT '',
+  `next_run` timestamp NULL DEFAULT NULL,
+  `last_run` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `schedules_project` (`project_id`),
+  KEY `schedules_next_run` (`next_run`),
+  CONSTRAINT `schedules_project` FOREIGN KEY (`project_id`) REFERENCES `projects` (`id`) ON DELETE CASCADE
+);
\ No newline at end of file
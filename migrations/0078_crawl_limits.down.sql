@@ -0,0 +1,3 @@
+ALTER TABLE `projects` DROP COLUMN `max_urls`;
+ALTER TABLE `projects` DROP COLUMN `max_duration`;
+ALTER TABLE `crawls` DROP COLUMN `stop_reason`;
\ No newline at end of file
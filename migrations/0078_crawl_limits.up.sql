@@ -0,0 +1,3 @@
+ALTER TABLE `projects` ADD COLUMN `max_urls` int NOT NULL DEFAULT '0';
+ALTER TABLE `projects` ADD COLUMN `max_duration` int NOT NULL DEFAULT '0';
+ALTER TABLE `crawls` ADD COLUMN `stop_reason` varchar(32) NOT NULL DEFAULT '';
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE `pagereports` DROP COLUMN `content_encoding`;
+ALTER TABLE `pagereports` DROP COLUMN `transfer_size`;
+DELETE FROM issue_types WHERE id = 75;
\ No newline at end of file
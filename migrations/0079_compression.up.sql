@@ -0,0 +1,4 @@
+ALTER TABLE `pagereports` ADD COLUMN `content_encoding` varchar(32) NOT NULL DEFAULT '';
+ALTER TABLE `pagereports` ADD COLUMN `transfer_size` int NOT NULL DEFAULT '0';
+
+INSERT INTO issue_types (id, type, priority) VALUES(75, "ERROR_UNCOMPRESSED", 3);
\ No newline at end of file
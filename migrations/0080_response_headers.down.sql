@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `response_headers`;
\ No newline at end of file
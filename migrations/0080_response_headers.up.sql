@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `response_headers` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `pagereport_id` int unsigned NOT NULL,
+  `crawl_id` int unsigned NOT NULL,
+  `name` varchar(256) NOT NULL DEFAULT '',
+  `value` varchar(2048) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`),
+  KEY `response_headers_pagereport` (`pagereport_id`),
+  KEY `response_headers_crawl_name` (`crawl_id`, `name`),
+  CONSTRAINT `response_headers_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `response_headers_pagereport` FOREIGN KEY (`pagereport_id`) REFERENCES `pagereports` (`id`) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `tls_hosts`;
+DELETE FROM issue_types WHERE id IN (76, 77, 78);
\ No newline at end of file
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS `tls_hosts` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `crawl_id` int unsigned NOT NULL,
+  `host` varchar(256) NOT NULL DEFAULT '',
+  `url` varchar(2048) NOT NULL DEFAULT '',
+  `url_hash` varchar(256) NOT NULL DEFAULT '',
+  `protocol` varchar(32) NOT NULL DEFAULT '',
+  `tls_version` varchar(32) NOT NULL DEFAULT '',
+  `cipher_suite` varchar(128) NOT NULL DEFAULT '',
+  `subject` varchar(512) NOT NULL DEFAULT '',
+  `issuer` varchar(512) NOT NULL DEFAULT '',
+  `sans` text,
+  `chain` text,
+  `not_after` datetime NULL DEFAULT NULL,
+  `hostname_mismatch` tinyint NOT NULL DEFAULT '0',
+  `obsolete_version` tinyint NOT NULL DEFAULT '0',
+  `error` varchar(1024) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`),
+  KEY `tls_hosts_crawl` (`crawl_id`),
+  CONSTRAINT `tls_hosts_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE
+);
+
+INSERT INTO issue_types (id, type, priority) VALUES(76, "ERROR_CERTIFICATE_EXPIRING", 1);
+INSERT INTO issue_types (id, type, priority) VALUES(77, "ERROR_CERTIFICATE_HOSTNAME", 1);
+INSERT INTO issue_types (id, type, priority) VALUES(78, "ERROR_OBSOLETE_TLS", 2);
\ No newline at end of file
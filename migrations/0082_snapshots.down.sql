@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `snapshots`;
+ALTER TABLE `projects` DROP COLUMN `store_snapshots`;
\ No newline at end of file
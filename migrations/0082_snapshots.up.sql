@@ -0,0 +1,15 @@
+ALTER TABLE `projects` ADD COLUMN `store_snapshots` tinyint NOT NULL DEFAULT '0';
+
+CREATE TABLE IF NOT EXISTS `snapshots` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `pagereport_id` int unsigned NOT NULL,
+  `crawl_id` int unsigned NOT NULL,
+  `url_hash` varchar(256) NOT NULL DEFAULT '',
+  `size` int NOT NULL DEFAULT '0',
+  `body` mediumblob NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `snapshots_pagereport` (`pagereport_id`),
+  KEY `snapshots_crawl_url` (`crawl_id`, `url_hash`),
+  KEY `snapshots_url` (`url_hash`),
+  CONSTRAINT `snapshots_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE
+);
\ No newline at end of file
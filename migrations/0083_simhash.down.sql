@@ -0,0 +1,2 @@
+ALTER TABLE `pagereports` DROP COLUMN `simhash`;
+DELETE FROM issue_types WHERE id = 79;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE `pagereports` ADD COLUMN `simhash` bigint NOT NULL DEFAULT '0';
+
+INSERT INTO issue_types (id, type, priority) VALUES(79, "ERROR_NEAR_DUPLICATE_CONTENT", 3);
\ No newline at end of file
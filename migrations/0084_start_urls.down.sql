@@ -0,0 +1,2 @@
+ALTER TABLE `projects` DROP COLUMN `start_urls`;
+ALTER TABLE `crawls` DROP COLUMN `start_urls`;
\ No newline at end of file
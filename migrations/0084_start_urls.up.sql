@@ -0,0 +1,2 @@
+ALTER TABLE `projects` ADD COLUMN `start_urls` text;
+ALTER TABLE `crawls` ADD COLUMN `start_urls` text;
\ No newline at end of file
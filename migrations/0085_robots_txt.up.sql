@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS `robots_txt` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `crawl_id` int unsigned NOT NULL,
+  `host` varchar(256) NOT NULL DEFAULT '',
+  `url` varchar(2048) NOT NULL DEFAULT '',
+  `status_code` int NOT NULL DEFAULT '0',
+  `body` mediumtext,
+  PRIMARY KEY (`id`),
+  KEY `robots_txt_crawl` (`crawl_id`),
+  CONSTRAINT `robots_txt_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE `projects` DROP COLUMN `robots_override`;
+ALTER TABLE `crawls` DROP COLUMN `robots_override`;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE `projects` ADD COLUMN `robots_override` text;
+ALTER TABLE `crawls` ADD COLUMN `robots_override` tinyint NOT NULL DEFAULT '0';
\ No newline at end of file
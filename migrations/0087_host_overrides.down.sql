@@ -0,0 +1 @@
+ALTER TABLE `projects` DROP COLUMN `host_overrides`;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `projects` ADD COLUMN `host_overrides` text;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `projects` DROP COLUMN `local_path`;
\ No newline at end of file
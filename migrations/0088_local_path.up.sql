@@ -0,0 +1 @@
+ALTER TABLE `projects` ADD COLUMN `local_path` varchar(512);
\ No newline at end of file
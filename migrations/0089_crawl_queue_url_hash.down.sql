@@ -0,0 +1,2 @@
+ALTER TABLE `crawl_queue` DROP INDEX `crawl_queue_url_hash`;
+ALTER TABLE `crawl_queue` DROP COLUMN `url_hash`;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE `crawl_queue` ADD COLUMN `url_hash` varchar(256) NOT NULL DEFAULT '';
+UPDATE `crawl_queue` SET `url_hash` = SHA2(`url`, 256);
+ALTER TABLE `crawl_queue` ADD INDEX `crawl_queue_url_hash` (`crawl_id`, `url_hash`);
\ No newline at end of file
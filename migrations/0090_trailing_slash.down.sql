@@ -0,0 +1 @@
+ALTER TABLE `projects` DROP COLUMN `trailing_slash`;
\ No newline at end of file
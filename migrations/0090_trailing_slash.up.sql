@@ -0,0 +1 @@
+ALTER TABLE `projects` ADD COLUMN `trailing_slash` varchar(16) NOT NULL DEFAULT '';
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `redirect_hops` DROP COLUMN `blocked`;
\ No newline at end of file
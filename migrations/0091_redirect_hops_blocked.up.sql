@@ -0,0 +1 @@
+ALTER TABLE `redirect_hops` ADD COLUMN `blocked` tinyint NOT NULL DEFAULT '0';
\ No newline at end of file
@@ -0,0 +1 @@
+DELETE FROM issue_types WHERE id = 80;
\ No newline at end of file
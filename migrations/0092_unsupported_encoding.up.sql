@@ -0,0 +1 @@
+INSERT INTO issue_types (id, type, priority) VALUES(80, "ERROR_UNSUPPORTED_ENCODING", 2);
\ No newline at end of file
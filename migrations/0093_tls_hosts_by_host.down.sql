@@ -0,0 +1,3 @@
+ALTER TABLE `tls_hosts` DROP INDEX `tls_hosts_host`;
+ALTER TABLE `tls_hosts` ADD COLUMN `url_hash` varchar(256) NOT NULL DEFAULT '';
+ALTER TABLE `pagereports` DROP COLUMN `host`;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE `pagereports` ADD COLUMN `host` varchar(256) NOT NULL DEFAULT '';
+ALTER TABLE `tls_hosts` DROP COLUMN `url_hash`;
+ALTER TABLE `tls_hosts` ADD INDEX `tls_hosts_host` (`crawl_id`, `host`);
\ No newline at end of file
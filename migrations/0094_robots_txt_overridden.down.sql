@@ -0,0 +1 @@
+ALTER TABLE `robots_txt` DROP COLUMN `overridden`;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `robots_txt` ADD COLUMN `overridden` tinyint NOT NULL DEFAULT '0';
\ No newline at end of file